@@ -1,40 +1,61 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"io/fs"
 	"log"
-	"net/http"
 	"os"
-	"time"
+	"os/signal"
+	"syscall"
 
 	"github.com/loicsikidi/wif-go/cmd/server/static"
+	"github.com/loicsikidi/wif-go/pkg/server"
 )
 
-const PORT = "8080"
+func main() {
+	os.Exit(run())
+}
 
-var httpRoot http.FileSystem
+func run() int {
+	config, err := server.LoadConfig(os.Args[1:], os.LookupEnv, os.Stderr)
+
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	} else if err != nil {
+		log.Printf("invalid configuration: %s", err)
+		return 2
+	}
+
+	var root fs.FS
+	switch {
+	case config.StaticDir != "":
+		root = os.DirFS(config.StaticDir)
+	case static.IsEmbedded:
+		root, err = fs.Sub(static.Box, "kodata")
 
-func main() {
-	if static.IsEmbedded {
-		contentStatic, err := fs.Sub(static.Box, "kodata")
 		if err != nil {
-			panic(err)
+			log.Printf("error loading the embedded playground: %s", err)
+			return 1
 		}
-		httpRoot = http.FS(contentStatic)
-	} else {
-		httpRoot = http.Dir(os.Getenv("KO_DATA_PATH"))
+	default:
+		log.Printf("no playground to serve, set --static-dir or build with the 'embed' tag")
 	}
 
-	http.Handle("/", http.FileServer(httpRoot))
-	log.Printf("server is listening on :%s", PORT)
+	srv, err := server.New(config, root)
 
-	server := &http.Server{
-		Addr:              ":" + PORT,
-		ReadHeaderTimeout: 15 * time.Second,
+	if err != nil {
+		log.Printf("error creating the server: %s", err)
+		return 1
 	}
 
-	err := server.ListenAndServe()
-	if err != nil {
-		panic(err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := srv.Run(ctx); err != nil {
+		log.Printf("%s", err)
+		return 1
 	}
+	return 0
 }
@@ -0,0 +1,22 @@
+//go:build js && wasm && !extended
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/token"
+)
+
+// registerExtensions registers the functions of the extended build, there is none in the default build
+func registerExtensions(r *Runner) {}
+
+// verify returns the JSON payload of the token of the options, the provider config isn't supported
+// as verifying a JWT links in the crypto packages (cf. the extended build)
+func (o *RunOptions) verify(backend provider.Backend) (string, error) {
+	if o.ProviderConfig != nil {
+		return "", fmt.Errorf("the provider config requires the extended build of wif-go.wasm (-tags extended)")
+	}
+	return token.Payload(o.Payload)
+}
@@ -0,0 +1,173 @@
+//go:build js && wasm && extended
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+	"unicode/utf16"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/complete"
+	"github.com/loicsikidi/wif-go/pkg/resource"
+	"github.com/loicsikidi/wif-go/pkg/validate"
+)
+
+// The extended build (-tags extended) adds wif_validate, wif_complete and the provider config of wif_run,
+// they aren't used by the playground and would make the default wif-go.wasm heavier (cf. size_test.go)
+
+// CompleteOptions are the options of wif_complete
+type CompleteOptions struct {
+	// [Optional] AttributeMapping provides the custom attributes suggested in an attribute condition.
+	AttributeMapping map[string]string `json:"attributeMapping"`
+	// [Optional] Condition completes an attribute condition rather than an attribute mapping.
+	Condition bool `json:"condition"`
+	// [Optional] Mode is either workload (default) or workforce.
+	Mode string `json:"mode"`
+}
+
+// registerExtensions registers the functions of the extended build
+func registerExtensions(r *Runner) {
+	js.Global().Set("wif_validate", asyncFuncOf(r.Validate))
+	js.Global().Set("wif_complete", asyncFuncOf(r.Complete))
+}
+
+// verify checks the token of the options against the provider config (cf. resource.Provider.Verify)
+// and returns its JSON payload
+func (o *RunOptions) verify(backend provider.Backend) (string, error) {
+	p := &resource.Provider{Type: backend}
+
+	if c := o.ProviderConfig; c != nil {
+		if backend != provider.OIDC {
+			return "", fmt.Errorf("the provider config is only supported by the 'oidc' provider")
+		}
+		p.OIDC = &resource.OIDC{IssuerURI: c.IssuerURI, AllowedAudiences: c.AllowedAudiences, JWKSJSON: c.JWKSJSON}
+	}
+	return p.Verify(o.Payload)
+}
+
+// Validate checks the attribute mapping and the attribute condition of the options (cf. RunOptions) without evaluating them,
+// the payload and the provider config are ignored. It returns whether the configuration is valid and every diagnostic:
+// the attribute key (empty for the condition), the severity, the message and the range of the issue in the expression
+// (line, column, endLine and endColumn starting at 1, or 0 when the issue concerns the whole expression).
+// Columns are indexes of the JS string (ie. UTF-16 code units) like the offsets of Complete.
+func (r *Runner) Validate(this js.Value, args []js.Value) (any, error) {
+	if len(args) != 1 || args[0].Type() != js.TypeObject {
+		return nil, fmt.Errorf("validate function expect an options object")
+	}
+
+	opts, err := parseRunOptions(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := opts.backend()
+
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := provider.New(b)
+
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := validate.Options{Workforce: opts.Mode == ModeWorkforce}.Validate(backend, opts.AttributeMapping, opts.AttributeCondition)
+	out := make([]any, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		expr := opts.AttributeCondition
+		if d.Attribute != "" {
+			expr = opts.AttributeMapping[d.Attribute]
+		}
+
+		out = append(out, map[string]any{
+			"attribute": d.Attribute,
+			"severity":  d.Severity,
+			"message":   d.Message,
+			"line":      d.Line,
+			"column":    utf16Column(expr, d.Line, d.Column),
+			"endLine":   d.EndLine,
+			"endColumn": utf16Column(expr, d.EndLine, d.EndColumn),
+		})
+	}
+	return map[string]any{"valid": validate.Valid(diagnostics), "diagnostics": out}, nil
+}
+
+// utf16Column converts a column of a line of an expression, counted in runes from 1, to UTF-16 code units
+func utf16Column(expr string, line, column int) int {
+	lines := strings.Split(expr, "\n")
+	if line < 1 || line > len(lines) || column < 1 {
+		return column
+	}
+
+	runes := []rune(lines[line-1])
+	if n := column - 1; n <= len(runes) {
+		return len(utf16.Encode(runes[:n])) + 1
+	}
+	// eg. the column after the last character
+	return len(utf16.Encode(runes)) + column - len(runes)
+}
+
+// Complete suggests what may be written at an offset of an expression, it accepts the expression, the offset,
+// the payload whose claims are suggested (ignored when it isn't a valid token) and optional options (cf. CompleteOptions).
+// It returns the range replaced by a suggestion (from and to) and the suggestions (label, kind, type and detail).
+// Offsets are indexes of the JS string.
+func (r *Runner) Complete(this js.Value, args []js.Value) (any, error) {
+	if argLength := len(args); argLength < 3 || argLength > 4 {
+		return nil, fmt.Errorf("complete function expect 3 to 4 args, got %d", argLength)
+	}
+
+	opts := &CompleteOptions{}
+	if len(args) == 4 && args[3].Type() == js.TypeObject {
+		raw := js.Global().Get("JSON").Call("stringify", args[3]).String()
+
+		if err := json.Unmarshal([]byte(raw), opts); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	if opts.Mode != "" && opts.Mode != ModeWorkload && opts.Mode != ModeWorkforce {
+		return nil, fmt.Errorf("unknown mode %q. Only '%s' and '%s' are accepted", opts.Mode, ModeWorkload, ModeWorkforce)
+	}
+
+	// the token is likely being edited, its claims are just left out
+	claims, _ := complete.Claims(args[2].String())
+
+	c := &complete.Completer{
+		Claims:           claims,
+		AttributeMapping: opts.AttributeMapping,
+		Condition:        opts.Condition,
+		Workforce:        opts.Mode == ModeWorkforce,
+	}
+
+	text := args[0].String()
+	units := utf16.Encode([]rune(text))
+	offset := args[1].Int()
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(units) {
+		offset = len(units)
+	}
+
+	res := c.Complete(text, len(utf16.Decode(units[:offset])))
+	suggestions := make([]any, 0, len(res.Suggestions))
+	for _, s := range res.Suggestions {
+		suggestions = append(suggestions, map[string]any{
+			"label":  s.Label,
+			"kind":   s.Kind,
+			"type":   s.Type,
+			"detail": s.Detail,
+		})
+	}
+
+	runes := []rune(text)
+	return map[string]any{
+		"from":        len(utf16.Encode(runes[:res.From])),
+		"to":          len(utf16.Encode(runes[:res.To])),
+		"suggestions": suggestions,
+	}, nil
+}
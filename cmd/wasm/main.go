@@ -3,7 +3,10 @@
 package main
 
 import (
+	"fmt"
 	"syscall/js"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
 )
 
 var Promise = js.Global().Get("Promise")
@@ -13,6 +16,7 @@ func main() {
 
 	js.Global().Set("wif_run", asyncFuncOf(runner.Run))
 	js.Global().Set("wif_version", asyncFuncOf(runner.Version))
+	registerExtensions(runner)
 	select {}
 }
 
@@ -26,9 +30,16 @@ func asyncFuncOf(fn func(this js.Value, args []js.Value) (any, error)) js.Func {
 			reject := promise[1]
 
 			go func() {
+				// a panic would terminate the module, the promise is rejected instead
+				defer func() {
+					if r := recover(); r != nil {
+						reject.Invoke(errorOf(fmt.Errorf("unexpected error: %v", r)))
+					}
+				}()
+
 				res, err := fn(this, args)
 				if err != nil {
-					reject.Invoke(err.Error())
+					reject.Invoke(errorOf(err))
 					return
 				}
 				resolve.Invoke(res)
@@ -40,3 +51,10 @@ func asyncFuncOf(fn func(this js.Value, args []js.Value) (any, error)) js.Func {
 		return Promise.New(handler)
 	})
 }
+
+// errorOf converts an error to a JS Error holding its category (cf. compiler.ErrorCategory)
+func errorOf(err error) js.Value {
+	e := js.Global().Get("Error").New(err.Error())
+	e.Set("category", compiler.ErrorCategory(err))
+	return e
+}
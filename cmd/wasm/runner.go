@@ -3,17 +3,45 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"syscall/js"
 
 	"github.com/loicsikidi/wif-go/pkg/compiler"
-	"github.com/loicsikidi/wif-go/pkg/compiler/provider/oidc"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
 )
 
 var Version string
 
-type Runner struct {
-	c *compiler.Compiler
+// Modes of wif_run.
+const (
+	ModeWorkload  = "workload"
+	ModeWorkforce = "workforce"
+)
+
+type Runner struct{}
+
+// ProviderConfig is the configuration of an OIDC provider (cf. the REST representation of a provider)
+type ProviderConfig struct {
+	IssuerURI        string   `json:"issuerUri"`
+	AllowedAudiences []string `json:"allowedAudiences"`
+	JWKSJSON         string   `json:"jwksJson"`
+}
+
+// RunOptions are the options of wif_run
+type RunOptions struct {
+	// [Required] Payload is the token, either a JWT or a JSON document holding the claims.
+	Payload string `json:"payload"`
+	// [Optional] Provider is the provider type (oidc, aws or saml), it defaults to oidc.
+	Provider string `json:"provider"`
+	// [Optional] ProviderConfig checks the token against the provider (issuer, audiences and signature), it requires the extended build.
+	ProviderConfig *ProviderConfig `json:"providerConfig"`
+	// [Required] AttributeMapping maps the token claims to Google Cloud attributes.
+	AttributeMapping map[string]string `json:"attributeMapping"`
+	// [Optional] AttributeCondition accepts or rejects the token.
+	AttributeCondition string `json:"attributeCondition"`
+	// [Optional] Mode is either workload (default) or workforce.
+	Mode string `json:"mode"`
 }
 
 // convertJsObjectValueToMap converts a js.Value object to a map[string]any
@@ -30,30 +58,84 @@ func convertJsObjectValueToMap(payload js.Value) map[string]string {
 	return payloadMap
 }
 
-func (r *Runner) Run(this js.Value, args []js.Value) (any, error) {
-	if argLength := len(args); argLength < 2 || argLength > 3 {
-		return nil, fmt.Errorf("run function expect 2 or 3 args, got %d", argLength)
+// parseRunOptions reads the options of wif_run, either an options object
+// or the positional arguments (payload, attribute mapping and an optional attribute condition)
+func parseRunOptions(args []js.Value) (*RunOptions, error) {
+	if len(args) == 1 && args[0].Type() == js.TypeObject {
+		opts := &RunOptions{}
+		raw := js.Global().Get("JSON").Call("stringify", args[0]).String()
+
+		if err := json.Unmarshal([]byte(raw), opts); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+		return opts, nil
 	}
 
-	payload := args[0].String()
-	attrMapping := convertJsObjectValueToMap(args[1])
+	if argLength := len(args); argLength < 2 || argLength > 3 {
+		return nil, fmt.Errorf("run function expect an options object or 2 to 3 args, got %d", argLength)
+	}
 
-	var attrCondition string
+	opts := &RunOptions{
+		Payload:          args[0].String(),
+		AttributeMapping: convertJsObjectValueToMap(args[1]),
+	}
 
 	if len(args) == 3 {
-		attrCondition = args[2].String()
+		opts.AttributeCondition = args[2].String()
+	}
+	return opts, nil
+}
+
+// backend returns the provider type of the options
+func (o *RunOptions) backend() (provider.Backend, error) {
+	if o.Mode != "" && o.Mode != ModeWorkload && o.Mode != ModeWorkforce {
+		return 0, fmt.Errorf("unknown mode %q. Only '%s' and '%s' are accepted", o.Mode, ModeWorkload, ModeWorkforce)
+	}
+
+	if o.Provider == "" {
+		return provider.OIDC, nil
+	}
+	return provider.ParseBackend(o.Provider)
+}
+
+// Run evaluates a token, it accepts an options object (cf. RunOptions)
+// or the positional arguments payload, attribute mapping and attribute condition
+func (r *Runner) Run(this js.Value, args []js.Value) (any, error) {
+	opts, err := parseRunOptions(args)
+
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := opts.backend()
+
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := opts.verify(backend)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := provider.New(backend)
+
+	if err != nil {
+		return nil, err
 	}
 
-	r.c = &compiler.Compiler{
+	c := &compiler.Compiler{
 		Input: &compiler.Input{
 			Payload:            payload,
-			AttributeMapping:   attrMapping,
-			AttributeCondition: attrCondition,
+			AttributeMapping:   opts.AttributeMapping,
+			AttributeCondition: opts.AttributeCondition,
 		},
-		Provider: &oidc.Provider{},
+		Provider:  p,
+		Workforce: opts.Mode == ModeWorkforce,
 	}
 
-	res, err := r.c.Run()
+	res, err := c.Run()
 
 	if err != nil {
 		return nil, err
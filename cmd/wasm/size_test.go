@@ -0,0 +1,114 @@
+//go:build !(js && wasm)
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// MaximumSizeInBytes is the size budget of wif-go.wasm built like the playground one (cf. make build-wasm),
+// it's the size of wif-go.wasm before the extended build was introduced (18,753,826 bytes) so that any growth is noticed
+const MaximumSizeInBytes = 18_753_826
+
+// MaximumExtendedSizeInBytes is the size budget of the extended build (cf. extended.go),
+// just above its measured size (21,074,692 bytes) so that any growth is noticed
+const MaximumExtendedSizeInBytes = 21_080_000
+
+// forbidden are packages which must not be linked into wif-go.wasm (eg. gRPC comes with the generated protobuf package)
+var forbidden = []string{
+	"github.com/loicsikidi/wif-go/pkg/generated/protobuf",
+	"google.golang.org/grpc",
+	"net/http",
+}
+
+// extendedOnly are packages which are only linked into the extended build (eg. the crypto packages verifying JWT signatures)
+var extendedOnly = []string{
+	"github.com/loicsikidi/wif-go/pkg/complete",
+	"github.com/loicsikidi/wif-go/pkg/resource",
+	"github.com/loicsikidi/wif-go/pkg/token/inspect",
+	"github.com/loicsikidi/wif-go/pkg/token/jwks",
+	"github.com/loicsikidi/wif-go/pkg/validate",
+	"crypto",
+	"encoding/xml",
+}
+
+var builds = []struct {
+	tags        string
+	maximumSize int64
+	forbidden   []string
+}{
+	{
+		maximumSize: MaximumSizeInBytes,
+		forbidden:   append(append([]string{}, forbidden...), extendedOnly...),
+	},
+	{
+		tags:        "extended",
+		maximumSize: MaximumExtendedSizeInBytes,
+		forbidden:   forbidden,
+	},
+}
+
+func wasmCommand(t *testing.T, args ...string) *exec.Cmd {
+	t.Helper()
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't available")
+	}
+
+	cmd := exec.Command("go", args...)
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	return cmd
+}
+
+func TestDependencies(t *testing.T) {
+	for i, tst := range builds {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			out, err := wasmCommand(t, "list", "-tags", tc.tags, "-deps", ".").Output()
+
+			if err != nil {
+				t.Fatalf("go list -tags %q = %s, expected no error", tc.tags, err)
+			}
+
+			for _, dep := range strings.Fields(string(out)) {
+				for _, f := range tc.forbidden {
+					if dep == f || strings.HasPrefix(dep, f+"/") {
+						t.Errorf("wif-go.wasm (-tags %q) depends on %s", tc.tags, dep)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("building wif-go.wasm is slow")
+	}
+
+	for i, tst := range builds {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			wasm := filepath.Join(t.TempDir(), "wif-go.wasm")
+			if out, err := wasmCommand(t, "build", "-tags", tc.tags, "-trimpath", "-ldflags", "-s -w", "-o", wasm, ".").CombinedOutput(); err != nil {
+				t.Fatalf("go build -tags %q = %s, expected no error\n%s", tc.tags, err, out)
+			}
+
+			info, err := os.Stat(wasm)
+
+			if err != nil {
+				t.Fatalf("Stat() = %s, expected no error", err)
+			}
+
+			t.Logf("wif-go.wasm (-tags %q) is %.1f MiB", tc.tags, float64(info.Size())/(1<<20))
+			if info.Size() > tc.maximumSize {
+				t.Fatalf("wif-go.wasm (-tags %q) is %d bytes, expected at most %d", tc.tags, info.Size(), tc.maximumSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/config"
+	"github.com/loicsikidi/wif-go/pkg/diff"
+)
+
+// parseInterspersedFlags parses flags placed before, between or after positional arguments
+// (eg. wif diff old.yaml new.yaml --corpus tokens/) and returns the positional arguments
+func parseInterspersedFlags(fs *flag.FlagSet, args []string) ([]string, int, bool) {
+	var positional []string
+
+	for {
+		if code, ok := parseFlags(fs, args); !ok {
+			return nil, code, false
+		}
+		if fs.NArg() == 0 {
+			return positional, 0, true
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+type jsonOutcome struct {
+	Status        string         `json:"status"`
+	Error         string         `json:"error,omitempty"`
+	ErrorCategory string         `json:"errorCategory,omitempty"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+}
+
+type jsonAttributeChange struct {
+	Attribute string `json:"attribute"`
+	Old       any    `json:"old"`
+	New       any    `json:"new"`
+}
+
+type jsonChange struct {
+	Token      string                 `json:"token"`
+	Flipped    bool                   `json:"flipped"`
+	Old        *jsonOutcome           `json:"old"`
+	New        *jsonOutcome           `json:"new"`
+	Attributes []*jsonAttributeChange `json:"attributes,omitempty"`
+}
+
+type jsonDiffReport struct {
+	Tokens  int           `json:"tokens"`
+	Gained  int           `json:"gained"`
+	Lost    int           `json:"lost"`
+	Changes []*jsonChange `json:"changes"`
+}
+
+func newJSONOutcome(o *diff.Outcome) *jsonOutcome {
+	out := &jsonOutcome{Status: o.Status, Attributes: o.Attributes}
+	if o.Err != nil {
+		out.Error = o.Err.Error()
+		out.ErrorCategory = compiler.ErrorCategory(o.Err)
+	}
+	return out
+}
+
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("diff", "--corpus <dir> [--resource <name>] [--format text|json] <old> <new>", stderr)
+	corpus := fs.String("corpus", "", "directory holding the tokens (JWT or JSON claims)")
+	resource := fs.String("resource", "", "name of the provider resource when a Terraform file declares several providers")
+	format := fs.String("format", formatText, "output format (text or json)")
+
+	files, code, ok := parseInterspersedFlags(fs, args)
+	if !ok {
+		return code
+	}
+
+	if len(files) != 2 || *corpus == "" {
+		fs.Usage()
+		return exitError
+	}
+	if *format != formatText && *format != "json" {
+		fmt.Fprintf(stderr, "error: unknown format %q. Only '%s' and 'json' are accepted\n", *format, formatText)
+		return exitError
+	}
+
+	var configs []*config.Provider
+	for _, path := range files {
+		c, err := diff.LoadConfig(path, *resource)
+
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			return exitCode(err)
+		}
+		configs = append(configs, c)
+	}
+
+	tokens, err := diff.LoadCorpus(*corpus)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitCode(err)
+	}
+
+	report := diff.Run(configs[0], configs[1], tokens)
+
+	if *format == "json" {
+		out := &jsonDiffReport{Tokens: report.Tokens, Gained: report.Gained, Lost: report.Lost, Changes: []*jsonChange{}}
+		for _, c := range report.Changes {
+			jc := &jsonChange{Token: c.Token.Name, Flipped: c.Flipped(), Old: newJSONOutcome(c.Old), New: newJSONOutcome(c.New)}
+			for _, a := range c.Attributes {
+				jc.Attributes = append(jc.Attributes, &jsonAttributeChange{Attribute: a.Name, Old: a.Old, New: a.New})
+			}
+			out.Changes = append(out.Changes, jc)
+		}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			return exitError
+		}
+	} else {
+		printDiff(stdout, report)
+	}
+
+	// like diff(1), differences are reported with 1
+	if len(report.Changes) > 0 {
+		return exitRejected
+	}
+	return exitAccepted
+}
+
+func printDiff(w io.Writer, report *diff.Report) {
+	for _, c := range report.Changes {
+		if c.Flipped() {
+			fmt.Fprintf(w, "%s: %s -> %s\n", c.Token.Name, c.Old.Status, c.New.Status)
+		} else {
+			fmt.Fprintf(w, "%s: %s\n", c.Token.Name, c.New.Status)
+		}
+
+		switch {
+		case c.ErrorChanged():
+			fmt.Fprintf(w, "    - %s\n", c.Old.Err)
+			fmt.Fprintf(w, "    + %s\n", c.New.Err)
+		case c.New.Status == diff.StatusError && c.Flipped():
+			fmt.Fprintf(w, "    %s\n", c.New.Err)
+		}
+		for _, a := range c.Attributes {
+			switch {
+			case a.Old == nil:
+				fmt.Fprintf(w, "    + %s: %s\n", a.Name, formatValue(a.New))
+			case a.New == nil:
+				fmt.Fprintf(w, "    - %s: %s\n", a.Name, formatValue(a.Old))
+			default:
+				fmt.Fprintf(w, "    ~ %s: %s -> %s\n", a.Name, formatValue(a.Old), formatValue(a.New))
+			}
+		}
+	}
+
+	if len(report.Changes) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d token(s), %d changed: %d gained access, %d lost access\n", report.Tokens, len(report.Changes), report.Gained, report.Lost)
+}
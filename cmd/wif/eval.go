@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func runEval(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("eval", "--token <file> (--mapping <file> [--condition <expr>] [--provider oidc] | --terraform <file> [--resource <name>]) [--watch]", stderr)
+	input := &inputFlags{}
+	input.register(fs)
+	watchFiles := fs.Bool("watch", false, "re-evaluate when the token, mapping, condition or Terraform files change")
+	interval := fs.Duration("watch-interval", defaultWatchInterval, "how often watched files are checked")
+
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if !*watchFiles {
+		return evalOnce(input, stdout, stderr)
+	}
+
+	if input.token == "-" || input.mapping == "-" || input.conditionFile == "-" {
+		fmt.Fprintf(stderr, "error: %s: stdin can't be watched\n", errUsage)
+		return exitError
+	}
+
+	var (
+		previous map[string]any
+		status   string
+	)
+
+	return watch(stdout, *interval, input.files, func() {
+		attributes, err := evaluate(input)
+		current := statusOf(err)
+
+		if current != status && status != "" {
+			fmt.Fprintf(stdout, "%s -> %s\n", status, current)
+		} else {
+			fmt.Fprintln(stdout, current)
+		}
+		status = current
+
+		if err != nil {
+			fmt.Fprintf(stdout, "error: %s\n", err)
+			return
+		}
+
+		if previous == nil {
+			fmt.Fprintln(stdout, formatAttributes(attributes))
+		} else if lines := diffLines(previous, attributes); len(lines) == 0 {
+			fmt.Fprintln(stdout, "no attribute changed")
+		} else {
+			fmt.Fprintln(stdout, strings.Join(lines, "\n"))
+		}
+		previous = attributes
+	})
+}
+
+// evalOnce evaluates the input and prints the derived attributes
+func evalOnce(input *inputFlags, stdout, stderr io.Writer) int {
+	attributes, err := evaluate(input)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitCode(err)
+	}
+
+	fmt.Fprintln(stdout, formatAttributes(attributes))
+	return exitAccepted
+}
+
+// evaluate runs the compiler described by the flags
+func evaluate(input *inputFlags) (map[string]any, error) {
+	c, err := input.compiler()
+
+	if err != nil {
+		return nil, err
+	}
+	return c.Run()
+}
+
+// statusOf describes the outcome of an evaluation
+func statusOf(err error) string {
+	switch exitCode(err) {
+	case exitAccepted:
+		return "ACCEPTED"
+	case exitRejected:
+		return "REJECTED"
+	default:
+		return "ERROR"
+	}
+}
+
+func formatAttributes(attributes map[string]any) string {
+	out, err := json.MarshalIndent(attributes, "", "  ")
+
+	if err != nil {
+		return fmt.Sprintf("%v", attributes)
+	}
+	return string(out)
+}
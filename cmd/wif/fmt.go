@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/format"
+	"gopkg.in/yaml.v3"
+)
+
+func runFmt(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("fmt", "[--width <n>] [--minify] [-w | --check] [<file>...]", stderr)
+	width := fs.Int("width", format.DefaultWidth, "column after which expressions are broken at '&&' and '||' (negative to break at every operator)")
+	minify := fs.Bool("minify", false, "print expressions on a single line without optional spaces")
+	write := fs.Bool("w", false, "write the result to the file instead of stdout")
+	check := fs.Bool("check", false, "exit with 1 when a file isn't formatted")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: wif fmt [--width <n>] [--minify] [-w | --check] [<file>...]\n\n")
+		fmt.Fprintf(stderr, "Files ending with .yaml, .yml or .json hold an attribute mapping, other files an attribute condition.\n")
+		fmt.Fprintf(stderr, "The attribute condition is read from stdin when no file is given.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	opts := &format.Options{Width: *width, Minify: *minify}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	code := exitAccepted
+	for _, path := range files {
+		raw, err := readFile(path)
+
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			return exitError
+		}
+
+		out, err := formatFile(path, raw, opts)
+
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s: %s\n", path, err)
+			return exitInvalid
+		}
+
+		switch {
+		case *check:
+			if out != raw {
+				fmt.Fprintln(stdout, path)
+				code = exitRejected
+			}
+		case *write && path != "-":
+			if out != raw {
+				if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
+					fmt.Fprintf(stderr, "error: %s\n", err)
+					return exitError
+				}
+			}
+		default:
+			fmt.Fprint(stdout, out)
+		}
+	}
+	return code
+}
+
+// formatFile formats an attribute mapping or an attribute condition depending on the file extension,
+// mappings keep their format (ie. JSON or YAML)
+func formatFile(path, raw string, opts *format.Options) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	switch ext {
+	case ".yaml", ".yml", ".json":
+		mapping := map[string]string{}
+
+		// YAML is a superset of JSON
+		if err := yaml.Unmarshal([]byte(raw), &mapping); err != nil {
+			return "", fmt.Errorf("error decoding attribute mapping: %w", err)
+		}
+
+		formatted, err := format.Mapping(mapping, opts)
+
+		if err != nil {
+			return "", err
+		}
+
+		if ext == ".json" {
+			return encodeJSON(formatted)
+		}
+
+		out, err := yaml.Marshal(formatted)
+
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	default:
+		out, err := format.Expression(raw, opts)
+
+		if err != nil {
+			return "", err
+		}
+		return out + "\n", nil
+	}
+}
+
+// encodeJSON encodes an attribute mapping as indented JSON, keeping operators such as '&&' unescaped
+func encodeJSON(mapping map[string]string) (string, error) {
+	var sb strings.Builder
+
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(mapping); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
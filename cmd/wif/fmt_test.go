@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/format"
+)
+
+func TestFormatFile(t *testing.T) {
+	tests := []struct {
+		path     string
+		raw      string
+		expected string
+	}{
+		{
+			path: "mapping.json",
+			raw:  `{"google.subject":"assertion.sub", "attribute.admin": "assertion.role=='admin'&&assertion.org=='octo' ? 'yes' : 'no'"}`,
+			expected: `{
+  "attribute.admin": "(assertion.role == \"admin\" && assertion.org == \"octo\") ? \"yes\" : \"no\"",
+  "google.subject": "assertion.sub"
+}
+`,
+		},
+		{
+			path:     "mapping.yaml",
+			raw:      "google.subject: assertion.sub\n",
+			expected: "google.subject: assertion.sub\n",
+		},
+		{
+			path:     "condition.cel",
+			raw:      "assertion.org=='octo'",
+			expected: "assertion.org == \"octo\"\n",
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			out, err := formatFile(tc.path, tc.raw, &format.Options{Width: format.DefaultWidth})
+
+			if err != nil {
+				t.Fatalf("formatFile() = %s, expected no error", err)
+			}
+			if out != tc.expected {
+				t.Fatalf("formatFile(%s) = %q, expected %q", tc.path, out, tc.expected)
+			}
+
+			// formatting is idempotent, so --check accepts formatted files
+			again, err := formatFile(tc.path, out, &format.Options{Width: format.DefaultWidth})
+
+			if err != nil || again != out {
+				t.Fatalf("formatFile(%s) = %q, expected %q", tc.path, again, out)
+			}
+		})
+	}
+}
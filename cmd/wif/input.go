@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/config"
+	"github.com/loicsikidi/wif-go/pkg/token"
+	"gopkg.in/yaml.v3"
+)
+
+// inputFlags are the flags describing what to evaluate
+type inputFlags struct {
+	token         string
+	mapping       string
+	condition     string
+	conditionFile string
+	terraform     string
+	resource      string
+	backend       provider.Backend
+}
+
+func (f *inputFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.token, "token", "", "path to the token, either a JWT or a JSON document holding the claims ('-' for stdin)")
+	fs.StringVar(&f.mapping, "mapping", "", "path to the attribute mapping, a JSON or YAML map (eg. {\"google.subject\": \"assertion.sub\"})")
+	fs.StringVar(&f.condition, "condition", "", "attribute condition (CEL expression)")
+	fs.StringVar(&f.conditionFile, "condition-file", "", "path to the attribute condition")
+	fs.StringVar(&f.terraform, "terraform", "", "path to a Terraform file declaring the provider, instead of --mapping and --condition")
+	fs.StringVar(&f.resource, "resource", "", "name of the provider resource when the Terraform file declares several providers")
+	f.backend = provider.OIDC
+	fs.Func("provider", "provider type, only 'oidc' is supported for now (default oidc)", f.setBackend)
+}
+
+// setBackend parses --provider, backends which aren't supported yet (eg. aws) are rejected as a usage error
+func (f *inputFlags) setBackend(name string) error {
+	backend, err := provider.ParseBackend(name)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := provider.New(backend); err != nil {
+		return err
+	}
+	f.backend = backend
+	return nil
+}
+
+// files returns the files read by the flags
+func (f *inputFlags) files() []string {
+	var files []string
+	for _, path := range []string{f.token, f.mapping, f.conditionFile, f.terraform} {
+		if path != "" && path != "-" {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+func readFile(path string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// loadMapping reads an attribute mapping from a JSON or YAML file
+func loadMapping(path string) (map[string]string, error) {
+	raw, err := readFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := map[string]string{}
+
+	// YAML is a superset of JSON
+	if err := yaml.Unmarshal([]byte(raw), &mapping); err != nil {
+		return nil, fmt.Errorf("error decoding attribute mapping %s: %w", path, err)
+	}
+	return mapping, nil
+}
+
+// configuration returns the provider backend, the attribute mapping and the attribute condition described by the flags,
+// either imported from a Terraform file (--terraform) or given by --mapping and --condition (the mapping is nil without --mapping)
+func (f *inputFlags) configuration() (*config.Provider, error) {
+	if f.condition != "" && f.conditionFile != "" {
+		return nil, fmt.Errorf("%w: --condition and --condition-file are mutually exclusive", errUsage)
+	}
+
+	if f.terraform != "" {
+		if f.mapping != "" || f.condition != "" || f.conditionFile != "" {
+			return nil, fmt.Errorf("%w: --terraform and --mapping, --condition or --condition-file are mutually exclusive", errUsage)
+		}
+		return config.FromTerraform(f.terraform, f.resource)
+	}
+
+	c := &config.Provider{Backend: f.backend, AttributeCondition: f.condition}
+
+	if f.mapping != "" {
+		mapping, err := loadMapping(f.mapping)
+
+		if err != nil {
+			return nil, err
+		}
+		c.AttributeMapping = mapping
+	}
+
+	if f.conditionFile != "" {
+		condition, err := readFile(f.conditionFile)
+
+		if err != nil {
+			return nil, err
+		}
+		c.AttributeCondition = strings.TrimSpace(condition)
+	}
+	return c, nil
+}
+
+// compiler builds the compiler described by the flags
+func (f *inputFlags) compiler() (*compiler.Compiler, error) {
+	if f.token == "" || (f.mapping == "" && f.terraform == "") {
+		return nil, fmt.Errorf("%w: --token and --mapping (or --terraform) are required", errUsage)
+	}
+
+	rawToken, err := readFile(f.token)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := token.Payload(rawToken)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := f.configuration()
+
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := provider.New(c.Backend)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiler.Compiler{
+		Input: &compiler.Input{
+			Payload:            payload,
+			AttributeMapping:   c.AttributeMapping,
+			AttributeCondition: c.AttributeCondition,
+		},
+		Provider: p,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+)
+
+var Version string
+
+// Exit codes returned by the wif command.
+const (
+	// The credential is accepted (or every test case passed)
+	exitAccepted = 0
+	// The credential is rejected by the attribute condition (or a test case failed)
+	exitRejected = 1
+	// The configuration (eg. attribute mapping) or the token is invalid
+	exitInvalid = 2
+	// The command is misused or failed for an unexpected reason (eg. a missing file)
+	exitError = 3
+)
+
+// errUsage means that the command is misused
+var errUsage = errors.New("usage error")
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{name: "eval", summary: "Evaluate a token against an attribute mapping and condition", run: runEval},
+		{name: "diff", summary: "Compare the behavior of two configurations over a corpus of tokens", run: runDiff},
+		{name: "fmt", summary: "Print attribute mappings and conditions in their canonical form", run: runFmt},
+		{name: "repl", summary: "Explore a token interactively", run: runRepl},
+		{name: "test", summary: "Run declarative test suites", run: runTest},
+		{name: "token", summary: "Inspect a token (eg. wif token inspect token.jwt)", run: runToken},
+		{name: "version", summary: "Print the version", run: runVersion},
+		{name: "help", summary: "Print this help", run: runHelp},
+	}
+}
+
+// exitCode maps an error to an exit code
+func exitCode(err error) int {
+	var pathErr *fs.PathError
+
+	switch {
+	case err == nil:
+		return exitAccepted
+	case errors.Is(err, compiler.ErrAttrConditionFailed):
+		return exitRejected
+	case errors.Is(err, errUsage), errors.As(err, &pathErr):
+		return exitError
+	default:
+		return exitInvalid
+	}
+}
+
+// newFlagSet returns a flag set printing its usage on stderr
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: wif %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags of a command.
+// It returns false with the exit code when the command must stop (eg. -help).
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitAccepted, false
+		}
+		return exitError, false
+	}
+	return 0, true
+}
+
+func runVersion(_ []string, stdout, _ io.Writer) int {
+	fmt.Fprintln(stdout, Version)
+	return exitAccepted
+}
+
+func runHelp(_ []string, _, stderr io.Writer) int {
+	fmt.Fprintf(stderr, "wif emulates Workload Identity Federation.\n\nUsage: wif <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(stderr, "\nExit codes:\n")
+	fmt.Fprintf(stderr, "  %d  the credential is accepted\n", exitAccepted)
+	fmt.Fprintf(stderr, "  %d  the credential is rejected by the attribute condition (or a test case failed)\n", exitRejected)
+	fmt.Fprintf(stderr, "  %d  the configuration or the token is invalid\n", exitInvalid)
+	fmt.Fprintf(stderr, "  %d  usage error or unexpected failure\n", exitError)
+	return exitAccepted
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		runHelp(nil, stdout, stderr)
+		return exitError
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+	runHelp(nil, stdout, stderr)
+	return exitError
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
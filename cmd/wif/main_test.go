@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() = %s, expected no error", err)
+		}
+		return path
+	}
+
+	tokenFile := write("token.json", `{"sub": "repo:octo-org/octo-repo", "repository_owner": "octo-org"}`)
+	mappingFile := write("mapping.yaml", "google.subject: assertion.sub\nattribute.owner: assertion.repository_owner\n")
+	invalidMappingFile := write("invalid.yaml", "google.email: assertion.sub\n")
+	invalidTokenFile := write("invalid.json", `not a token`)
+	terraformFile := write("main.tf", `resource "google_iam_workload_identity_pool_provider" "github" {
+  workload_identity_pool_id          = "ci-pool"
+  workload_identity_pool_provider_id = "github"
+  attribute_mapping = {
+    "google.subject" = "assertion.sub"
+  }
+  attribute_condition = "assertion.repository_owner == 'octo-org'"
+  oidc {
+    issuer_uri = "https://token.actions.githubusercontent.com"
+  }
+}
+`)
+	otherTokenFile := write("other.json", `{"sub": "repo:evil-org/repo", "repository_owner": "evil-org"}`)
+
+	tests := []struct {
+		args     []string
+		expected int
+	}{
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile},
+			expected: exitAccepted,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile, "--condition", "attribute.owner == 'octo-org'", "--provider", "OIDC"},
+			expected: exitAccepted,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile, "--condition", "attribute.owner == 'other-org'"},
+			expected: exitRejected,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", invalidMappingFile},
+			expected: exitInvalid,
+		},
+		{
+			args:     []string{"eval", "--token", invalidTokenFile, "--mapping", mappingFile},
+			expected: exitInvalid,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile, "--condition", "attribute.owner =="},
+			expected: exitInvalid,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--terraform", terraformFile},
+			expected: exitAccepted,
+		},
+		{
+			args:     []string{"eval", "--token", otherTokenFile, "--terraform", terraformFile, "--resource", "github"},
+			expected: exitRejected,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--terraform", terraformFile, "--resource", "gitlab"},
+			expected: exitInvalid,
+		},
+		// usage errors
+		{
+			args:     []string{"eval", "--token", tokenFile, "--terraform", terraformFile, "--mapping", mappingFile},
+			expected: exitError,
+		},
+		{
+			args:     []string{},
+			expected: exitError,
+		},
+		{
+			args:     []string{"unknown"},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile, "--condition", "true", "--condition-file", mappingFile},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile, "--provider", "aws"},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile, "--provider", "saml"},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "--token", tokenFile, "--mapping", mappingFile, "--provider", "unknown"},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "--token", filepath.Join(dir, "missing.json"), "--mapping", mappingFile},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "--unknown-flag"},
+			expected: exitError,
+		},
+		{
+			args:     []string{"eval", "-help"},
+			expected: exitAccepted,
+		},
+		{
+			args:     []string{"version"},
+			expected: exitAccepted,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			if code := run(tc.args, &stdout, &stderr); code != tc.expected {
+				t.Fatalf("run(%v) = %d, expected %d\nstdout: %s\nstderr: %s", tc.args, code, tc.expected, stdout.String(), stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/config"
+	"github.com/loicsikidi/wif-go/pkg/repl"
+	"github.com/peterh/liner"
+)
+
+const replPrompt = "wif> "
+
+// defaultHistoryFile returns ~/.wif_history, or an empty string when the home directory is unknown
+func defaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".wif_history")
+}
+
+func runRepl(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("repl", "[--token <file>] [--mapping <file>] [--condition <expr>] [--provider oidc] [--terraform <file> [--resource <name>]]", stderr)
+	input := &inputFlags{}
+	input.register(fs)
+	history := fs.String("history", defaultHistoryFile(), "path to the history file (empty to disable)")
+
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	config, err := input.configuration()
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitCode(err)
+	}
+
+	p, err := provider.New(config.Backend)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitCode(err)
+	}
+
+	session, err := repl.New(p)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitError
+	}
+
+	if err := loadSession(session, input.token, config, stderr); err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitCode(err)
+	}
+
+	line := liner.NewLiner()
+	defer line.Close()
+
+	line.SetCtrlCAborts(true)
+	line.SetCompleter(session.Complete)
+
+	if *history != "" {
+		if f, err := os.Open(*history); err == nil {
+			_, _ = line.ReadHistory(f)
+			f.Close()
+		}
+		defer func() {
+			// the history may hold claims, keep it private
+			if f, err := os.OpenFile(*history, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600); err == nil {
+				_ = f.Chmod(0o600)
+				_, _ = line.WriteHistory(f)
+				f.Close()
+			}
+		}()
+	}
+
+	fmt.Fprintln(stdout, "Type ':help' to list commands, ':quit' or Ctrl-D to leave.")
+
+	for {
+		text, err := line.Prompt(replPrompt)
+
+		if errors.Is(err, io.EOF) || errors.Is(err, liner.ErrPromptAborted) {
+			return exitAccepted
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			return exitError
+		}
+
+		if recordable(text) {
+			line.AppendHistory(text)
+		}
+
+		out, err := session.Execute(text)
+
+		switch {
+		case errors.Is(err, repl.ErrQuit):
+			return exitAccepted
+		case err != nil:
+			fmt.Fprintf(stderr, "error: %s\n", err)
+		case out != "":
+			fmt.Fprintln(stdout, out)
+		}
+	}
+}
+
+// recordable tells whether a line can be saved in the history, tokens (ie. ':token <token>') are bearer credentials so they aren't
+func recordable(text string) bool {
+	fields := strings.Fields(text)
+	return len(fields) > 0 && fields[0] != repl.CommandPrefix+"token"
+}
+
+// loadSession initializes a session from the optional --token flag and the configuration (cf. inputFlags.configuration)
+func loadSession(s *repl.Session, tokenFile string, config *config.Provider, stderr io.Writer) error {
+	if tokenFile != "" {
+		raw, err := readFile(tokenFile)
+
+		if err != nil {
+			return err
+		}
+		if err := s.Load(raw); err != nil {
+			return err
+		}
+	}
+
+	if config.AttributeMapping != nil {
+		s.AttributeMapping = config.AttributeMapping
+	}
+	s.AttributeCondition = config.AttributeCondition
+
+	if tokenFile != "" && len(s.AttributeMapping) > 0 {
+		// derive the attributes so they can be used right away
+		if _, err := s.Run(); err != nil {
+			fmt.Fprintf(stderr, "warning: %s\n", err)
+		}
+	}
+	return nil
+}
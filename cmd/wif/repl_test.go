@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRecordable(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected bool
+	}{
+		{line: "assertion.sub", expected: true},
+		{line: ":map google.subject assertion.sub", expected: true},
+		{line: ":token eyJhbGciOiJSUzI1NiJ9.e30.c2ln", expected: false},
+		{line: "  :token {\"sub\": \"alice\"}", expected: false},
+		{line: "   ", expected: false},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			if got := recordable(tc.line); got != tc.expected {
+				t.Fatalf("recordable(%s) = %t, expected %t", tc.line, got, tc.expected)
+			}
+		})
+	}
+}
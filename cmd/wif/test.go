@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/loicsikidi/wif-go/pkg/testsuite"
+)
+
+// formatValue renders an attribute value in a diff
+func formatValue(v any) string {
+	if v == nil {
+		return "<missing>"
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
+
+func printReport(w io.Writer, report *testsuite.Report) {
+	fmt.Fprintf(w, "=== %s\n", report.Suite.Name)
+	for _, r := range report.Results {
+		if r.Passed {
+			fmt.Fprintf(w, "PASS  %s\n", r.Case.Name)
+			continue
+		}
+
+		fmt.Fprintf(w, "FAIL  %s\n", r.Case.Name)
+		for _, failure := range r.Failures {
+			fmt.Fprintf(w, "      %s\n", failure)
+		}
+		for _, d := range r.Diff {
+			fmt.Fprintf(w, "      %s:\n", d.Name)
+			fmt.Fprintf(w, "        - expected: %s\n", formatValue(d.Expected))
+			fmt.Fprintf(w, "        + actual:   %s\n", formatValue(d.Actual))
+		}
+	}
+}
+
+// formatText is the human readable format of test reports
+const formatText = "text"
+
+func runTest(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("test", "[--format text|json|junit|tap] [--output <file>] [--coverage] [--watch] <suite.yaml>...", stderr)
+	format := fs.String("format", formatText, "report format (text, json, junit or tap)")
+	output := fs.String("output", "", "path of the report (default: stdout)")
+	watchFiles := fs.Bool("watch", false, "re-run the suites when a suite, token or Terraform file changes")
+	interval := fs.Duration("watch-interval", defaultWatchInterval, "how often watched files are checked")
+	withCoverage := fs.Bool("coverage", false, "report the branches of the attribute conditions which aren't exercised by an accepted and a rejected case")
+
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitError
+	}
+
+	if !*watchFiles {
+		reports, code := runSuites(fs.Args(), stderr)
+
+		if err := writeReports(stdout, *format, *output, reports); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			return exitError
+		}
+
+		if *withCoverage {
+			// keep machine readable reports parsable
+			w := stdout
+			if *format != formatText && *output == "" {
+				w = stderr
+			}
+			printCoverage(w, reports)
+		}
+		return code
+	}
+
+	// the files referenced by a suite are read again on every check, so that a new token file is watched,
+	// and the last known ones are kept while the suite can't be loaded (eg. a syntax error being fixed)
+	references := map[string][]string{}
+	files := func() []string {
+		out := append([]string{}, fs.Args()...)
+		for _, path := range fs.Args() {
+			if suite, err := testsuite.Load(path); err == nil {
+				references[path] = suite.Files()
+			}
+			out = append(out, references[path]...)
+		}
+		return out
+	}
+	previous := map[string]bool{}
+
+	return watch(stdout, *interval, files, func() {
+		reports, _ := runSuites(fs.Args(), stdout)
+
+		if err := writeReports(stdout, *format, *output, reports); err != nil {
+			fmt.Fprintf(stdout, "error: %s\n", err)
+		}
+		if *withCoverage {
+			printCoverage(stdout, reports)
+		}
+
+		current := map[string]bool{}
+		for _, report := range reports {
+			for _, r := range report.Results {
+				id := report.Suite.Name + ": " + r.Case.Name
+				current[id] = r.Passed
+
+				if passed, ok := previous[id]; ok && passed != r.Passed {
+					fmt.Fprintf(stdout, "%s -> %s  %s\n", passStatus(passed), passStatus(r.Passed), id)
+				}
+			}
+		}
+		previous = current
+	})
+}
+
+// printCoverage prints the coverage of the attribute condition of each suite and its uncovered branches
+func printCoverage(w io.Writer, reports []*testsuite.Report) {
+	for _, report := range reports {
+		c, err := report.Suite.Coverage()
+
+		if err != nil {
+			fmt.Fprintf(w, "\ncoverage: %s: %s\n", report.Suite.Name, err)
+			continue
+		}
+
+		fmt.Fprintf(w, "\ncoverage: %s: %d/%d branches (%.1f%%)\n", report.Suite.Name, c.Covered(), len(c.Branches), c.Percent())
+		for _, b := range c.Branches {
+			if b.Covered() {
+				continue
+			}
+			fmt.Fprintf(w, "  %d:%d  %-13s %s  (accepted: %d, rejected: %d)\n", b.Line, b.Column, b.Kind, b.Expression, b.Accepted, b.Rejected)
+		}
+	}
+}
+
+func passStatus(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+// runSuites loads and runs test suites, it returns the reports and the exit code
+func runSuites(paths []string, stderr io.Writer) ([]*testsuite.Report, int) {
+	var reports []*testsuite.Report
+	failed := 0
+	code := exitAccepted
+
+	for _, path := range paths {
+		suite, err := testsuite.Load(path)
+
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			code = exitCode(err)
+			continue
+		}
+
+		report, err := suite.Run()
+
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s: %s\n", path, err)
+			code = exitCode(err)
+			continue
+		}
+
+		reports = append(reports, report)
+		failed += report.Failed
+	}
+
+	if code == exitAccepted && failed > 0 {
+		return reports, exitRejected
+	}
+	return reports, code
+}
+
+// writeReports writes test reports in the given format, either to a file or to stdout
+func writeReports(stdout io.Writer, format, output string, reports []*testsuite.Report) error {
+	w := stdout
+
+	if output != "" {
+		f, err := os.Create(output)
+
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format != formatText {
+		return testsuite.Write(w, format, reports...)
+	}
+
+	passed, failed := 0, 0
+	for _, report := range reports {
+		printReport(w, report)
+		passed += report.Passed
+		failed += report.Failed
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed\n", passed, failed)
+	return err
+}
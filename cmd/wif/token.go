@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/token/inspect"
+)
+
+func runToken(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "inspect" {
+		fmt.Fprintf(stderr, "Usage: wif token inspect [--format text|json] <file>\n")
+		return exitError
+	}
+	return runTokenInspect(args[1:], stdout, stderr)
+}
+
+func runTokenInspect(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("token inspect", "[--format text|json] <file>", stderr)
+	format := fs.String("format", formatText, "output format (text or json)")
+
+	if code, ok := parseFlags(fs, args); !ok {
+		return code
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitError
+	}
+
+	raw, err := readFile(fs.Arg(0))
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitError
+	}
+
+	inspection, err := inspect.Inspect(raw)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err)
+		return exitInvalid
+	}
+
+	switch *format {
+	case formatText:
+		printInspection(stdout, inspection, time.Now())
+	case "json":
+		out := struct {
+			*inspect.Inspection
+			Paths []string `json:"paths"`
+		}{inspection, inspection.Paths()}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		if err := enc.Encode(out); err != nil {
+			fmt.Fprintf(stderr, "error: %s\n", err)
+			return exitError
+		}
+	default:
+		fmt.Fprintf(stderr, "error: unknown format %q. Only '%s' and 'json' are accepted\n", *format, formatText)
+		return exitError
+	}
+	return exitAccepted
+}
+
+func printInspection(w io.Writer, i *inspect.Inspection, now time.Time) {
+	fmt.Fprintf(w, "Kind: %s\n", i.Kind)
+
+	if i.Header != nil {
+		fmt.Fprintf(w, "\nHeader:\n%s\n", formatAttributes(i.Header))
+	}
+	fmt.Fprintf(w, "\nClaims:\n%s\n", formatAttributes(i.Claims))
+
+	if len(i.Times) > 0 {
+		fmt.Fprintf(w, "\nTimes:\n")
+		for _, t := range i.Times {
+			fmt.Fprintf(w, "  %-13s %s (%s)\n", t.Name, t.Time.Format(time.RFC3339), relativeTime(t.Time, now))
+		}
+	}
+
+	if len(i.Issues) > 0 {
+		fmt.Fprintf(w, "\nWarnings:\n")
+		for _, issue := range i.Issues {
+			fmt.Fprintf(w, "  %s: %s\n", issue.Path, issue.Message)
+		}
+	}
+
+	fmt.Fprintf(w, "\nClaim paths:\n")
+	for _, path := range i.Paths() {
+		fmt.Fprintf(w, "  %s\n", path)
+	}
+}
+
+// relativeTime describes a time relatively to now (eg. 3h ago, in 5m)
+func relativeTime(t, now time.Time) string {
+	d := t.Sub(now)
+	past := d < 0
+	if past {
+		d = -d
+	}
+
+	var s string
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		s = fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		s = fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		s = fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+
+	if past {
+		return s + " ago"
+	}
+	return "in " + s
+}
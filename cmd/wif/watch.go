@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// defaultWatchInterval is how often watched files are checked
+const defaultWatchInterval = 500 * time.Millisecond
+
+// fileState identifies a version of a file
+type fileState struct {
+	modTime time.Time
+	size    int64
+	missing bool
+}
+
+// watcher detects changes of a set of files by polling their modification time and size.
+// Polling keeps the tool portable and dependency free, editors often replace files rather than writing them in place.
+type watcher struct {
+	// files returns the watched files, it's called on every check since the set may change (eg. a new token file)
+	files  func() []string
+	states map[string]fileState
+}
+
+func newWatcher(files func() []string) *watcher {
+	w := &watcher{files: files}
+	w.changed()
+	return w
+}
+
+// changed returns the files modified, created or removed since the last call.
+// Files which join the set aren't reported, they were read by the last run (eg. a token referenced by a suite).
+func (w *watcher) changed() []string {
+	states := map[string]fileState{}
+	var changed []string
+
+	for _, path := range w.files() {
+		state := fileState{missing: true}
+		if info, err := os.Stat(path); err == nil {
+			state = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		states[path] = state
+
+		if previous, ok := w.states[path]; ok && previous != state {
+			changed = append(changed, path)
+		}
+	}
+
+	w.states = states
+	return changed
+}
+
+// watch calls run once, then every time a file changes, until SIGINT or SIGTERM
+func watch(stdout io.Writer, interval time.Duration, files func() []string, run func()) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the files are recorded before the first run so that the edits made while it runs are noticed
+	w := newWatcher(files)
+	run()
+	fmt.Fprintf(stdout, "\nwatching %d file(s) for changes, press Ctrl-C to stop\n", len(w.files()))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return exitAccepted
+		case <-ticker.C:
+			changed := w.changed()
+			if len(changed) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(stdout, "\n[%s] changed: %v\n", time.Now().Format(time.TimeOnly), changed)
+			run()
+		}
+	}
+}
+
+// diffLines describes the changes between two sets of derived attributes
+// ('+' added, '-' removed, '~' modified)
+func diffLines(previous, current map[string]any) []string {
+	keys := map[string]bool{}
+	for k := range previous {
+		keys[k] = true
+	}
+	for k := range current {
+		keys[k] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, k := range sorted {
+		before, existed := previous[k]
+		after, exists := current[k]
+		b, a := formatValue(before), formatValue(after)
+
+		switch {
+		case !existed:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", k, a))
+		case !exists:
+			lines = append(lines, fmt.Sprintf("- %s: %s", k, b))
+		case a != b:
+			lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", k, b, a))
+		}
+	}
+	return lines
+}
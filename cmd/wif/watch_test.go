@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	suite := filepath.Join(dir, "suite.yaml")
+	token := filepath.Join(dir, "token.json")
+
+	touch := func(path, content string, at time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() = %s, expected no error", err)
+		}
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatalf("Chtimes() = %s, expected no error", err)
+		}
+	}
+
+	now := time.Now()
+	touch(suite, "cases: []", now)
+	touch(token, `{"sub": "alice"}`, now)
+
+	files := []string{suite}
+	w := newWatcher(func() []string { return files })
+
+	if changed := w.changed(); len(changed) != 0 {
+		t.Fatalf("changed() = %v, expected no change", changed)
+	}
+
+	// a file joining the set isn't a change
+	files = append(files, token)
+	if changed := w.changed(); len(changed) != 0 {
+		t.Fatalf("changed() = %v, expected no change when a file is first seen", changed)
+	}
+
+	touch(token, `{"sub": "bob"}`, now.Add(time.Second))
+	if changed := w.changed(); !reflect.DeepEqual(changed, []string{token}) {
+		t.Fatalf("changed() = %v, expected %v", changed, []string{token})
+	}
+
+	if err := os.Remove(suite); err != nil {
+		t.Fatalf("Remove() = %s, expected no error", err)
+	}
+	if changed := w.changed(); !reflect.DeepEqual(changed, []string{suite}) {
+		t.Fatalf("changed() = %v, expected %v", changed, []string{suite})
+	}
+
+	// fixing a missing file is a change
+	touch(suite, "cases: []", now.Add(2*time.Second))
+	if changed := w.changed(); !reflect.DeepEqual(changed, []string{suite}) {
+		t.Fatalf("changed() = %v, expected %v", changed, []string{suite})
+	}
+}
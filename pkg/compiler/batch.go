@@ -0,0 +1,62 @@
+package compiler
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Result is the outcome of the evaluation of a payload of a batch
+type Result struct {
+	// Attributes are the derived attributes of an accepted payload.
+	Attributes map[string]any
+	// Err tells why the payload isn't accepted (eg. ErrAttrConditionFailed).
+	Err error
+}
+
+// RunBatch evaluates payloads against the attribute mapping and the attribute condition (Input.Payload is ignored).
+//
+// The expressions are compiled once and the payloads are evaluated by at most workers goroutines
+// (runtime.GOMAXPROCS when workers isn't positive). Results are in the order of the payloads
+// and each one holds the outcome Run would return for its payload, so an invalid configuration is reported by every result.
+func (c *Compiler) RunBatch(payloads []string, workers int) []*Result {
+	results := make([]*Result, len(payloads))
+
+	// as with Run, an invalid input (eg. an empty payload or an invalid mapping key) is reported without decoding the payload
+	var p *Program
+	var compileErr error
+	if c.validateMappingKeys() == nil {
+		p, compileErr = c.compile()
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(payloads) {
+		workers = len(payloads)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := c.checkInput(payloads[i]); err != nil {
+					results[i] = &Result{Err: err}
+					continue
+				}
+
+				attributes, err := c.run(payloads[i], p, compileErr)
+				results[i] = &Result{Attributes: attributes, Err: err}
+			}
+		}()
+	}
+
+	for i := range payloads {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
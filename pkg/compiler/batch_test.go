@@ -0,0 +1,75 @@
+package compiler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/oidc"
+)
+
+func TestRunBatch(t *testing.T) {
+	c := &Compiler{
+		Input: &Input{
+			AttributeMapping:   map[string]string{GoogleSubject: "assertion.sub"},
+			AttributeCondition: "assertion.org == 'octo-org'",
+		},
+		Provider: &oidc.Provider{},
+	}
+
+	var payloads []string
+	for i := 0; i < 50; i++ {
+		payloads = append(payloads, fmt.Sprintf(`{"sub": "user-%d", "org": "octo-org"}`, i))
+	}
+	payloads = append(payloads, `{"sub": "bob", "org": "evil-org"}`, `not-json`, `{"org": "octo-org"}`)
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		results := c.RunBatch(payloads, workers)
+
+		if len(results) != len(payloads) {
+			t.Fatalf("RunBatch(%d) = %d results, expected %d", workers, len(results), len(payloads))
+		}
+
+		for i := 0; i < 50; i++ {
+			if r := results[i]; r.Err != nil || r.Attributes[GoogleSubject] != fmt.Sprintf("user-%d", i) {
+				t.Fatalf("RunBatch(%d)[%d] = %v, expected user-%d", workers, i, r, i)
+			}
+		}
+
+		for i, expected := range []string{CategoryConditionFailed, CategoryInvalidToken, CategoryEvaluation} {
+			if category := ErrorCategory(results[50+i].Err); category != expected {
+				t.Fatalf("RunBatch(%d)[%d] = %s, expected %s", workers, 50+i, category, expected)
+			}
+		}
+	}
+
+	c.Input.AttributeCondition = invalidCelExpr
+	results := c.RunBatch(payloads[:2], 2)
+	for i, r := range results {
+		if ErrorCategory(r.Err) != CategoryCompilation {
+			t.Fatalf("RunBatch()[%d] = %v, expected a compilation error", i, r.Err)
+		}
+	}
+
+	c.Input.AttributeMapping = map[string]string{"google.email": "assertion.sub"}
+	for i, r := range c.RunBatch(payloads[51:], 2) {
+		if ErrorCategory(r.Err) != CategoryInvalidInput {
+			t.Fatalf("RunBatch()[%d] = %v, expected an invalid input", i, r.Err)
+		}
+	}
+
+	// an empty payload gives the outcome of Run
+	c.Input.AttributeMapping = map[string]string{GoogleSubject: "assertion.sub"}
+	c.Input.Payload = ""
+	_, runErr := c.Run()
+	if r := c.RunBatch([]string{""}, 1)[0]; ErrorCategory(r.Err) != CategoryInvalidInput || r.Err.Error() != runErr.Error() {
+		t.Fatalf("RunBatch(\"\") = %v, expected %v", r.Err, runErr)
+	}
+
+	if results := c.RunBatch(nil, 0); len(results) != 0 {
+		t.Fatalf("RunBatch(nil) = %v, expected no result", results)
+	}
+
+	if _, err := (&Compiler{Provider: &oidc.Provider{}}).Compile(); ErrorCategory(err) != CategoryInvalidInput {
+		t.Fatalf("Compile() = %v, expected an invalid input", err)
+	}
+}
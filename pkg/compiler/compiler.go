@@ -1,12 +1,12 @@
 package compiler
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
@@ -28,6 +28,16 @@ const (
 	GoogleGroups = "google.groups"
 )
 
+// Standard google attributes only available with Workforce Identity Federation (cf. Compiler.Workforce).
+const (
+	// Attribute representing the name of a user in the Google Cloud console.
+	GoogleDisplayName = "google.display_name"
+	// Attribute representing the URL of the thumbnail photo of a user.
+	GoogleProfilePhoto = "google.profile_photo"
+	// Attribute representing the POSIX username of a user.
+	GooglePosixUsername = "google.posix_username"
+)
+
 // Limitations set by Google Cloud Platform.
 //
 // (See more at https://cloud.google.com/iam/quotas#limits)
@@ -44,6 +54,8 @@ const (
 	MaximumAttributeConditionLengthInBytes = 4096
 	// Workload identity federation can't exceed 50 custom attributes
 	MaximumCustomAttributes = 50
+	// google.display_name can't exceed 100 characters
+	MaximumDisplayNameLength = 100
 )
 
 // ErrAttrConditionFailed means that a credential
@@ -70,30 +82,65 @@ type Compiler struct {
 	Input *Input
 	// Target Provider supported by Workload Identity Federation  (eg. OIDC, SAML, etc.)
 	Provider provider.Provider
+	// Workforce targets a Workforce Identity Federation provider, which accepts more google attributes
+	// (ie. google.display_name, google.profile_photo and google.posix_username).
+	Workforce bool
+}
+
+// Eval compiles and evaluates a CEL expression
+func Eval(env *cel.Env, input map[string]any, expr string) (ref.Val, error) {
+	prg, err := compile(env, expr)
+
+	if err != nil {
+		return nil, err
+	}
+	return eval(prg, input)
 }
 
-// eval is a helper function that compiles and evaluates a CEL expression
-func eval(env *cel.Env, input map[string]any, expr string) (ref.Val, error) {
+// compile compiles a CEL expression into a program
+func compile(env *cel.Env, expr string) (cel.Program, error) {
 	if strings.Contains(expr, "timestamp(int(") {
-		return nil, fmt.Errorf("create a timestamp using unix timestamp is not currently supported by the Workload Identity Federation CEL implementation")
+		return nil, newError(CategoryCompilation, fmt.Errorf("create a timestamp using unix timestamp is not currently supported by the Workload Identity Federation CEL implementation"))
 	}
 
 	ast, issues := env.Compile(expr)
 
 	if issues.Err() != nil {
-		return nil, fmt.Errorf("error compiling CEL expression: %w", issues.Err())
+		return nil, newError(CategoryCompilation, fmt.Errorf("error compiling CEL expression: %w", issues.Err()))
 	}
 
 	prg, _ := env.Program(ast)
+	return prg, nil
+}
+
+// eval evaluates a CEL program
+func eval(prg cel.Program, input map[string]any) (ref.Val, error) {
 	result, _, err := prg.Eval(input)
 
 	if err != nil {
-		return nil, fmt.Errorf("error evaluating CEL expression: %w", err)
+		return nil, newError(CategoryEvaluation, fmt.Errorf("error evaluating CEL expression: %w", err))
 	}
 
 	return result, nil
 }
 
+// NewEnv returns the CEL environment of a provider, including Workload Identity Federation custom functions
+func NewEnv(p provider.Provider) (*cel.Env, error) {
+	return cel.NewEnv(addCustomFn(p.GetOptions())...)
+}
+
+// ConditionInput returns the variables available in an attribute condition (ie. assertion, google and attribute)
+// from the variables of a provider and the derived attributes
+func ConditionInput(input map[string]any, derivedAttributes map[string]any) (map[string]any, error) {
+	// the variables of the providers are decoded JSON values, so they're used as is
+	variables, err := attribute.Variables(util.MergeMaps(input, derivedAttributes))
+
+	if err != nil {
+		return nil, newError(CategoryInternal, fmt.Errorf("error producing attribute input var: %w", err))
+	}
+	return variables, nil
+}
+
 // addCustomFn adds Workload Identity Federation custom functions to the CEL environment
 func addCustomFn(opts []cel.EnvOption) []cel.EnvOption {
 	for _, fn := range allFns.ProvideAll() {
@@ -109,24 +156,6 @@ func getCustomAttr(attributes map[string]any) []string {
 	})
 }
 
-// convertProtoMapToRegularMap converts a map of proto to
-// a map of regular type (eg. string, int32, float64, etc.)
-func convertProtoMapToRegularMap(input map[string]any) (map[string]any, error) {
-	var _json any
-
-	rawJSON, err := util.JSONEncode(input)
-
-	if err != nil {
-		return nil, err
-	}
-
-	if err := json.Unmarshal([]byte(rawJSON), &_json); err != nil {
-		return nil, err
-	}
-
-	return _json.(map[string]any), nil
-}
-
 // checkGoogleGroupsValue checks if the value of the google.groups attribute is valid
 func checkGoogleGroupsValue(list ref.Val) error {
 	if list.Type() != types.ListType {
@@ -143,40 +172,159 @@ func checkGoogleGroupsValue(list ref.Val) error {
 	return nil
 }
 
-// Run compiles a Workload Identity Federation expression and returns a map of derived attributes
-func (c *Compiler) Run() (map[string]any, error) {
-	derivedAttributes := map[string]any{}
+// Program is a compiled attribute mapping and attribute condition.
+// It's safe for concurrent use, so that several payloads can be evaluated without compiling the expressions again.
+type Program struct {
+	provider provider.Provider
+	// mapping are the programs of the attribute mapping, by attribute
+	mapping map[string]cel.Program
+	// condition is the program of the attribute condition, nil without condition
+	condition cel.Program
+}
+
+// Compile compiles the attribute mapping and the attribute condition of the input (Input.Payload is ignored)
+func (c *Compiler) Compile() (*Program, error) {
+	if err := c.validateMappingKeys(); err != nil {
+		return nil, err
+	}
+	return c.compile()
+}
 
-	// Input validation
-	if c.Input == nil || c.Input.Payload == "" || c.Input.AttributeMapping == nil {
-		return nil, fmt.Errorf("input is invalid. Payload and AttributeMapping are required")
+// validateMappingKeys checks the keys of the attribute mapping,
+// their errors are reported before the ones of the payload (cf. Run)
+func (c *Compiler) validateMappingKeys() error {
+	if c.Input == nil || c.Input.AttributeMapping == nil {
+		return newError(CategoryInvalidInput, fmt.Errorf("input is invalid. AttributeMapping is required"))
 	}
 
-	// Input.AttributeMapping validation
 	for k := range c.Input.AttributeMapping {
-		if !strings.HasPrefix(k, fmt.Sprintf("%s.", attribute.Attribute)) && k != GoogleSubject && k != GoogleGroups {
-			return nil, fmt.Errorf("invalid attribute mapping key: %s.\nOnly 'google.subject', 'google.groups' and 'attribute.<custom_attribute>' are accepted", k)
+		if err := InvalidMappingKey(k, c.Workforce); err != nil {
+			return newError(CategoryInvalidInput, err)
 		}
 	}
+	return nil
+}
 
-	input, err := c.Provider.GetInputVar(c.Input.Payload)
+// compile compiles the expressions of an attribute mapping whose keys are valid
+func (c *Compiler) compile() (*Program, error) {
+	env, err := NewEnv(c.Provider)
 
 	if err != nil {
+		return nil, newError(CategoryInternal, fmt.Errorf("error creating CEL environment: %w", err))
+	}
+
+	if err := c.preValidation(); err != nil {
+		return nil, newError(CategoryInvalidInput, err)
+	}
+
+	p := &Program{provider: c.Provider, mapping: map[string]cel.Program{}}
+	for k, v := range c.Input.AttributeMapping {
+		if p.mapping[k], err = compile(env, v); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Input.AttributeCondition != "" {
+		attrEnv, err := NewEnv(&attribute.Provider{})
+
+		if err != nil {
+			return nil, newError(CategoryInternal, fmt.Errorf("error creating attribute condition CEL environment: %w", err))
+		}
+
+		if p.condition, err = compile(attrEnv, c.Input.AttributeCondition); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// GoogleAttributes returns the google attributes which can be mapped,
+// workforce adds the ones of Workforce Identity Federation
+func GoogleAttributes(workforce bool) []string {
+	if workforce {
+		return []string{GoogleSubject, GoogleGroups, GoogleDisplayName, GoogleProfilePhoto, GooglePosixUsername}
+	}
+	return []string{GoogleSubject, GoogleGroups}
+}
+
+// InvalidMappingKey returns an error when an attribute can't be mapped (cf. GoogleAttributes)
+func InvalidMappingKey(k string, workforce bool) error {
+	if strings.HasPrefix(k, fmt.Sprintf("%s.", attribute.Attribute)) {
+		return nil
+	}
+
+	google := GoogleAttributes(workforce)
+	for _, g := range google {
+		if k == g {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid attribute mapping key: %s.\nOnly '%s' and 'attribute.<custom_attribute>' are accepted", k, strings.Join(google, "', '"))
+}
+
+// Run compiles a Workload Identity Federation expression and returns a map of derived attributes
+func (c *Compiler) Run() (map[string]any, error) {
+	var payload string
+	if c.Input != nil {
+		payload = c.Input.Payload
+	}
+
+	if err := c.checkInput(payload); err != nil {
 		return nil, err
 	}
 
-	env, err := cel.NewEnv(addCustomFn(c.Provider.GetOptions())...)
+	p, err := c.compile()
+	return c.run(payload, p, err)
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("error creating CEL environment: %w", err)
+// checkInput validates the input of a payload before compiling (cf. Run and RunBatch)
+func (c *Compiler) checkInput(payload string) error {
+	if c.Input == nil || payload == "" || c.Input.AttributeMapping == nil {
+		return newError(CategoryInvalidInput, fmt.Errorf("input is invalid. Payload and AttributeMapping are required"))
 	}
+	return c.validateMappingKeys()
+}
 
-	if err := c.preValidation(); err != nil {
+// run evaluates a payload with a compiled program,
+// compileErr (ie. the error of compile) is reported once the payload is decoded
+func (c *Compiler) run(payload string, p *Program, compileErr error) (map[string]any, error) {
+	if compileErr != nil {
+		if _, err := c.Provider.GetInputVar(payload); err != nil {
+			return nil, newError(CategoryInvalidToken, err)
+		}
+		return nil, compileErr
+	}
+	return p.Eval(payload)
+}
+
+// Eval derives the attributes of a payload and checks them against the attribute condition
+func (p *Program) Eval(payload string) (map[string]any, error) {
+	derivedAttributes, err := p.Derive(payload)
+
+	if err != nil {
 		return nil, err
 	}
+	return derivedAttributes, nil
+}
+
+// Derive is like Eval, but the derived attributes are also returned when the attribute condition
+// can't be evaluated or rejects them (ie. ErrAttrConditionFailed)
+func (p *Program) Derive(payload string) (map[string]any, error) {
+	input, err := p.provider.GetInputVar(payload)
+
+	if err != nil {
+		return nil, newError(CategoryInvalidToken, err)
+	}
+	return p.eval(input)
+}
 
-	for k, v := range c.Input.AttributeMapping {
-		val, err := eval(env, input, v)
+// eval derives the attributes from the variables of the provider and checks them against the attribute condition,
+// the derived attributes are returned along with the errors of the attribute condition
+func (p *Program) eval(input map[string]any) (map[string]any, error) {
+	derivedAttributes := map[string]any{}
+
+	for k, prg := range p.mapping {
+		val, err := eval(prg, input)
 
 		if err != nil {
 			return nil, err
@@ -185,7 +333,7 @@ func (c *Compiler) Run() (map[string]any, error) {
 		if k != GoogleGroups {
 			// nominal case: we expect a string
 			if val.Type() != types.StringType {
-				return nil, fmt.Errorf("the mapped attribute '%s' must be of type STRING", k)
+				return nil, newError(CategoryInvalidAttribute, fmt.Errorf("the mapped attribute '%s' must be of type STRING", k))
 			}
 			output := val.(types.String)
 			derivedAttributes[k] = string(output)
@@ -193,64 +341,43 @@ func (c *Compiler) Run() (map[string]any, error) {
 			// special case: we expect a list of strings
 			// The elements in mapped attribute 'google.groups' must be of type STRING.
 			if err := checkGoogleGroupsValue(val); err != nil {
-				return nil, err
+				return nil, newError(CategoryInvalidAttribute, err)
 			}
 
 			output, err := val.ConvertToNative(reflect.TypeOf([]any{}))
 
 			if err != nil {
-				return nil, err
+				return nil, newError(CategoryInvalidAttribute, err)
 			}
 
 			derivedAttributes[k] = output
 		}
 	}
 
-	if err := c.postValidation(derivedAttributes); err != nil {
-		return nil, err
+	if err := postValidation(derivedAttributes); err != nil {
+		return nil, newError(CategoryInvalidAttribute, err)
 	}
 
-	if c.Input.AttributeCondition != "" {
-		newInput, err := convertProtoMapToRegularMap(input)
-
-		if err != nil {
-			return nil, err
-		}
-
-		mergedMap := util.MergeMaps(newInput, derivedAttributes)
-
-		attributeProvider := attribute.Provider{}
-		inputVar, err := attribute.GetAttributeInputVar(mergedMap)
-
-		if err != nil {
-			return nil, fmt.Errorf("error producing attribute input var: %w", err)
-		}
-
-		attrInput, err := attributeProvider.GetInputVar(inputVar)
-
-		if err != nil {
-			return nil, err
-		}
-
-		attrEnv, err := cel.NewEnv(addCustomFn(attributeProvider.GetOptions())...)
+	if p.condition != nil {
+		attrInput, err := ConditionInput(input, derivedAttributes)
 
 		if err != nil {
-			return nil, fmt.Errorf("error creating attribute condition CEL environment: %w", err)
+			return derivedAttributes, err
 		}
 
-		val, err := eval(attrEnv, attrInput, c.Input.AttributeCondition)
+		val, err := eval(p.condition, attrInput)
 
 		if err != nil {
-			return nil, err
+			return derivedAttributes, err
 		}
 
 		if val.Type() != types.BoolType {
-			return nil, ErrAttrConditionFailed
+			return derivedAttributes, ErrAttrConditionFailed
 		}
 
 		condition := val.(types.Bool)
 		if !bool(condition) {
-			return nil, ErrAttrConditionFailed
+			return derivedAttributes, ErrAttrConditionFailed
 		}
 	}
 	return derivedAttributes, nil
@@ -283,7 +410,7 @@ func (c *Compiler) preValidation() error {
 }
 
 // postValidation validates derived attributes's conformity
-func (c *Compiler) postValidation(derivedAttributes map[string]any) error {
+func postValidation(derivedAttributes map[string]any) error {
 	if _, ok := derivedAttributes[GoogleSubject]; !ok {
 		return fmt.Errorf("missing '%s' attribute", GoogleSubject)
 	}
@@ -292,6 +419,10 @@ func (c *Compiler) postValidation(derivedAttributes map[string]any) error {
 		return fmt.Errorf("the size of mapped attribute '%s' exceeds the %d bytes limit", GoogleSubject, MaximumSubjectLengthInBytes)
 	}
 
+	if name, ok := derivedAttributes[GoogleDisplayName].(string); ok && utf8.RuneCountInString(name) > MaximumDisplayNameLength {
+		return fmt.Errorf("the mapped attribute '%s' exceeds the %d characters limit", GoogleDisplayName, MaximumDisplayNameLength)
+	}
+
 	mappedAttrSize, _ := util.Reduce(util.GetMapValues(derivedAttributes), 0, func(acc int, currentValue any, currentIndex int) int {
 		switch val := currentValue.(type) {
 		case string:
@@ -1,6 +1,7 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
@@ -356,3 +357,154 @@ func TestAttributeCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		input    *Input
+		expected string
+	}{
+		{
+			input:    &Input{},
+			expected: CategoryInvalidInput,
+		},
+		{
+			input:    &Input{Payload: `{sub: "1234567890"}`, AttributeMapping: map[string]string{GoogleSubject: "assertion.sub"}},
+			expected: CategoryInvalidToken,
+		},
+		// the attribute mapping keys are validated before the payload is decoded, the expressions after
+		{
+			input:    &Input{Payload: `{sub: "1234567890"}`, AttributeMapping: map[string]string{"google.email": "assertion.sub"}},
+			expected: CategoryInvalidInput,
+		},
+		{
+			input:    &Input{Payload: `{sub: "1234567890"}`, AttributeMapping: map[string]string{GoogleSubject: invalidCelExpr + "("}},
+			expected: CategoryInvalidToken,
+		},
+		{
+			input:    &Input{Payload: jwtPayloadBody, AttributeMapping: map[string]string{GoogleSubject: invalidCelExpr + "("}},
+			expected: CategoryCompilation,
+		},
+		{
+			input:    &Input{Payload: jwtPayloadBody, AttributeMapping: map[string]string{GoogleSubject: "assertion.missing"}},
+			expected: CategoryEvaluation,
+		},
+		{
+			input:    &Input{Payload: jwtPayloadBody, AttributeMapping: map[string]string{GoogleSubject: "true"}},
+			expected: CategoryInvalidAttribute,
+		},
+		{
+			input:    &Input{Payload: jwtPayloadBody, AttributeMapping: map[string]string{GoogleSubject: "assertion.sub"}, AttributeCondition: "assertion.is_admin == false"},
+			expected: CategoryConditionFailed,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			c := Compiler{
+				Input:    tc.input,
+				Provider: &oidc.Provider{},
+			}
+			_, err := c.Run()
+
+			if got := ErrorCategory(err); got != tc.expected {
+				t.Fatalf("ErrorCategory(%v) = %s, expected %s", err, got, tc.expected)
+			}
+		})
+	}
+
+	if got := ErrorCategory(errors.New("unknown")); got != CategoryInternal {
+		t.Fatalf("ErrorCategory() = %s, expected %s", got, CategoryInternal)
+	}
+}
+
+func TestWorkforce(t *testing.T) {
+	tests := []struct {
+		mapping   map[string]string
+		workforce bool
+		isError   bool
+	}{
+		{
+			mapping:   map[string]string{GoogleSubject: "assertion.sub", GoogleDisplayName: "assertion.name", GooglePosixUsername: "'jdoe'"},
+			workforce: true,
+		},
+		{
+			mapping: map[string]string{GoogleSubject: "assertion.sub", GoogleDisplayName: "assertion.name"},
+			isError: true,
+		},
+		{
+			mapping:   map[string]string{GoogleSubject: "assertion.sub", GoogleDisplayName: fmt.Sprintf("'%s'", generateStr(MaximumDisplayNameLength+1))},
+			workforce: true,
+			isError:   true,
+		},
+		{
+			mapping:   map[string]string{GoogleSubject: "assertion.sub", GoogleProfilePhoto: "assertion.iat"},
+			workforce: true,
+			isError:   true,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			c := &Compiler{
+				Input: &Input{
+					Payload:          `{"sub": "1234567890", "name": "John Doe", "iat": 1516239022}`,
+					AttributeMapping: tc.mapping,
+				},
+				Provider:  &oidc.Provider{},
+				Workforce: tc.workforce,
+			}
+			attributes, err := c.Run()
+
+			if tc.isError {
+				if err == nil {
+					t.Fatalf("Run(%v) -> expect exception", tc.mapping)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Run(%v) = %s, expected no error", tc.mapping, err)
+			}
+
+			if attributes[GoogleDisplayName] != "John Doe" {
+				t.Fatalf("Run(%v) = %v, expected %s to be 'John Doe'", tc.mapping, attributes, GoogleDisplayName)
+			}
+		})
+	}
+}
+
+func TestDerive(t *testing.T) {
+	c := &Compiler{
+		Input: &Input{
+			AttributeMapping:   map[string]string{GoogleSubject: "assertion.sub", "attribute.org": "assertion.org"},
+			AttributeCondition: "attribute.org == 'octo-org'",
+		},
+		Provider: &oidc.Provider{},
+	}
+
+	p, err := c.Compile()
+
+	if err != nil {
+		t.Fatalf("Compile() = %s, expected no error", err)
+	}
+
+	payload := `{"sub": "1234567890", "org": "evil-org"}`
+	attributes, err := p.Derive(payload)
+
+	if !errors.Is(err, ErrAttrConditionFailed) {
+		t.Fatalf("Derive() = %v, expected %s", err, ErrAttrConditionFailed)
+	}
+	if attributes["attribute.org"] != "evil-org" {
+		t.Fatalf("Derive() = %v, expected attribute.org to be 'evil-org'", attributes)
+	}
+
+	if attributes, err := p.Eval(payload); attributes != nil || !errors.Is(err, ErrAttrConditionFailed) {
+		t.Fatalf("Eval() = %v, %v, expected no attributes and %s", attributes, err, ErrAttrConditionFailed)
+	}
+
+	if attributes, err := p.Derive("not-json"); attributes != nil || ErrorCategory(err) != CategoryInvalidToken {
+		t.Fatalf("Derive() = %v, %v, expected an invalid token", attributes, err)
+	}
+}
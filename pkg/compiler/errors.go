@@ -0,0 +1,59 @@
+package compiler
+
+import "errors"
+
+// Categories of the errors returned by the compiler.
+const (
+	// The input is invalid (eg. missing payload, unauthorized attribute mapping key, expression too long)
+	CategoryInvalidInput = "invalid_input"
+	// The payload can't be decoded by the provider
+	CategoryInvalidToken = "invalid_token"
+	// A CEL expression can't be compiled
+	CategoryCompilation = "compilation"
+	// A CEL expression can't be evaluated (eg. missing claim)
+	CategoryEvaluation = "evaluation"
+	// A derived attribute doesn't comply with Google Cloud Platform limitations (eg. type, size)
+	CategoryInvalidAttribute = "invalid_attribute"
+	// The credential is rejected by the attribute condition (cf. ErrAttrConditionFailed)
+	CategoryConditionFailed = "condition_failed"
+	// Unexpected error
+	CategoryInternal = "internal"
+)
+
+// Error is an error returned by the compiler, its message is the one of the wrapped error
+type Error struct {
+	// Category of the error (eg. CategoryCompilation)
+	Category string
+	// Err is the wrapped error
+	Err error
+}
+
+func newError(category string, err error) error {
+	return &Error{Category: category, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCategory returns the category of an error returned by the compiler.
+// Errors not produced by the compiler belong to CategoryInternal.
+func ErrorCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, ErrAttrConditionFailed) {
+		return CategoryConditionFailed
+	}
+	// errors.As isn't used since it would link in reflection helpers (cf. cmd/wasm/size_test.go)
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*Error); ok {
+			return e.Category
+		}
+	}
+	return CategoryInternal
+}
@@ -95,6 +95,12 @@ func extract(source string, template string) (string, error) { //nolint:unparam
 	return match[1], nil
 }
 
+func (f *function) Signatures() []string {
+	return []string{
+		"<string>.extract(<string>) -> <string>",
+	}
+}
+
 func (f *function) GetFn() cel.EnvOption {
 	return cel.Function("extract",
 		cel.MemberOverload("string_extract_string",
@@ -17,6 +17,12 @@ type Interface interface {
 	GetFn() cel.EnvOption
 }
 
+// Documented is implemented by functions describing their overloads (eg. for the completion).
+type Documented interface {
+	// Signatures returns the overloads of the function (eg. <string>.extract(<string>) -> <string>).
+	Signatures() []string
+}
+
 // Register is used by functions to participate in furnishing OIDC tokens.
 func Register(name string, fn Interface) {
 	m.Lock()
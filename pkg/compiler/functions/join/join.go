@@ -51,6 +51,13 @@ func joinValSeparator(strs traits.Lister, separator string) (string, error) {
 	return sb.String(), nil
 }
 
+func (f *function) Signatures() []string {
+	return []string{
+		"<list<string>>.join() -> <string>",
+		"<list<string>>.join(<string>) -> <string>",
+	}
+}
+
 func (f *function) GetFn() cel.EnvOption {
 	return cel.Function("join",
 		cel.MemberOverload("list_join", []*cel.Type{cel.ListType(cel.StringType)}, cel.StringType,
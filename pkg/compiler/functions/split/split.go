@@ -44,6 +44,13 @@ func splitN(str, sep string, n int64) ([]string, error) { //nolint:unparam
 	return strings.SplitN(str, sep, int(n)), nil
 }
 
+func (f *function) Signatures() []string {
+	return []string{
+		"<string>.split(<string>) -> <list<string>>",
+		"<string>.split(<string>, <int>) -> <list<string>>",
+	}
+}
+
 func (f *function) GetFn() cel.EnvOption {
 	return cel.Function("split",
 		cel.MemberOverload("string_split_string", []*cel.Type{cel.StringType, cel.StringType}, cel.ListType(cel.StringType),
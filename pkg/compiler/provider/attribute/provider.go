@@ -7,8 +7,6 @@ import (
 
 	"github.com/google/cel-go/cel"
 	"github.com/loicsikidi/wif-go/pkg/common/util"
-	pb "github.com/loicsikidi/wif-go/pkg/generated/protobuf"
-	"google.golang.org/protobuf/types/known/structpb"
 )
 
 const (
@@ -31,26 +29,43 @@ func GetAssertionName(ass string) string {
 	return fmt.Sprintf("%s.%s", Assertion, ass)
 }
 
-func GetAttributeInputVar(customMap map[string]any) (string, error) {
-	attrMap := map[string]map[string]any{}
-	for _, v := range VARIABLES {
-		attrMap[v] = map[string]any{}
-	}
+// Variables returns the variables of an attribute condition (ie. attribute, google and assertion)
+// from the variables of a provider (eg. assertion) and the derived attributes (eg. google.subject)
+func Variables(customMap map[string]any) (map[string]any, error) {
+	attributes, google := map[string]any{}, map[string]any{}
 
 	for k, v := range customMap {
-		isDerivedClaim := util.Any([]string{Attribute, Google}, func(s any) bool {
-			return strings.HasPrefix(k, fmt.Sprintf("%s.", s))
-		})
-		if isDerivedClaim {
-			family := strings.Split(k, ".")[0]
-			name := strings.Split(k, ".")[1]
-			attrMap[family][name] = v
-		} else {
-			attrMap[k] = v.(map[string]any)
+		family, name, ok := strings.Cut(k, ".")
+		switch {
+		case ok && family == Attribute:
+			attributes[name] = v
+		case ok && family == Google:
+			google[name] = v
+		}
+	}
+
+	assertion := map[string]any{}
+	if v, ok := customMap[Assertion]; ok {
+		if assertion, ok = v.(map[string]any); !ok {
+			return nil, fmt.Errorf("error reading variable '%s': expected a JSON object", Assertion)
 		}
 	}
 
-	json, err := util.JSONEncode(attrMap)
+	return map[string]any{
+		Attribute: attributes,
+		Assertion: assertion,
+		Google:    google}, nil
+}
+
+// GetAttributeInputVar encodes the variables of an attribute condition (cf. Variables) into JSON, as read by Provider.GetInputVar
+func GetAttributeInputVar(customMap map[string]any) (string, error) {
+	variables, err := Variables(customMap)
+
+	if err != nil {
+		return "", err
+	}
+
+	json, err := util.JSONEncode(variables)
 
 	if err != nil {
 		return "", fmt.Errorf("error encoding custom attribute map into JSON: %w", err)
@@ -76,27 +91,14 @@ func (p *Provider) GetInputVar(raw string) (map[string]any, error) {
 		return nil, fmt.Errorf("error unmarshaling JSON: %w", err)
 	}
 
-	attribute := &pb.AttributeSchema{}
-	for k, v := range _json.(map[string]any) {
-		pb, err := structpb.NewValue(v)
-
-		if err != nil {
-			return nil, fmt.Errorf("error converting JSON to protobuf: %w", err)
-		}
-
-		if k == Google {
-			attribute.Google = pb
-		}
-		if k == Assertion {
-			attribute.Assertion = pb
-		}
-		if k == Attribute {
-			attribute.Attribute = pb
-		}
+	// decoded JSON values are used as is (cf. oidc.Provider)
+	variables, ok := _json.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("error reading attributes: expected a JSON object")
 	}
 
 	return map[string]any{
-		Attribute: attribute.Attribute,
-		Assertion: attribute.Assertion,
-		Google:    attribute.Google}, nil
+		Attribute: variables[Attribute],
+		Assertion: variables[Assertion],
+		Google:    variables[Google]}, nil
 }
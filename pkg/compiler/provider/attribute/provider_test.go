@@ -2,6 +2,7 @@ package attribute
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -33,3 +34,51 @@ func TestGetAttributeInputVar(t *testing.T) {
 		})
 	}
 }
+
+func TestGetInputVar(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected map[string]any
+		isError  bool
+	}{
+		{
+			raw: `{"assertion": {"sub": "123456789", "iat": 1700000000}, "attribute": {}, "google": {"groups": ["admins"]}}`,
+			expected: map[string]any{
+				Assertion: map[string]any{"sub": "123456789", "iat": float64(1700000000)},
+				Attribute: map[string]any{},
+				Google:    map[string]any{"groups": []any{"admins"}},
+			},
+		},
+		{
+			raw:      `{"google": {"subject": "123456789"}}`,
+			expected: map[string]any{Assertion: nil, Attribute: nil, Google: map[string]any{"subject": "123456789"}},
+		},
+		{
+			raw:     `["google"]`,
+			isError: true,
+		},
+	}
+
+	p := &Provider{}
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			out, err := p.GetInputVar(tc.raw)
+
+			if tc.isError {
+				if err == nil {
+					t.Fatalf("GetInputVar() -> expect exception")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("GetInputVar() = %s, expected no error", err)
+			}
+
+			if !reflect.DeepEqual(out, tc.expected) {
+				t.Fatalf("GetInputVar(%s) = %v, expected %v", tc.raw, out, tc.expected)
+			}
+		})
+	}
+}
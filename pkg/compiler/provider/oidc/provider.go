@@ -5,8 +5,6 @@ import (
 	"fmt"
 
 	"github.com/google/cel-go/cel"
-	pb "github.com/loicsikidi/wif-go/pkg/generated/protobuf"
-	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type Provider struct{}
@@ -24,15 +22,7 @@ func (p *Provider) GetInputVar(raw string) (map[string]any, error) {
 		return nil, fmt.Errorf("error unmarshaling JSON: %w", err)
 	}
 
-	_assertion, err := structpb.NewValue(_json)
-
-	if err != nil {
-		return nil, fmt.Errorf("error converting JSON to protobuf: %w", err)
-	}
-
-	assertion := &pb.OidcSchema{
-		Assertion: _assertion,
-	}
-
-	return map[string]any{"assertion": assertion.Assertion}, nil
+	// CEL adapts decoded JSON values natively (numbers are doubles like in a google.protobuf.Value),
+	// the generated protobuf schemas would link gRPC into the wasm bundle
+	return map[string]any{"assertion": _json}, nil
 }
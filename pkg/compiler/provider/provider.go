@@ -1,15 +1,59 @@
 package provider
 
-import "github.com/google/cel-go/cel"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/oidc"
+)
 
 const (
 	OIDC = iota
+	AWS
+	SAML
 )
 
 type Backend = int
 
+var backendNames = []string{
+	OIDC: "oidc",
+	AWS:  "aws",
+	SAML: "saml",
+}
+
 // Provider handles CEL logic per Workload Identity Federation provider type
 type Provider interface {
 	GetOptions() []cel.EnvOption
 	GetInputVar(raw string) (map[string]any, error)
 }
+
+// BackendName returns the name of a backend (eg. oidc, aws, saml)
+func BackendName(b Backend) string {
+	if b >= 0 && b < len(backendNames) {
+		return backendNames[b]
+	}
+	return fmt.Sprintf("unknown(%d)", b)
+}
+
+// ParseBackend returns the backend matching the given name (case insensitive)
+func ParseBackend(name string) (Backend, error) {
+	for b, n := range backendNames {
+		if strings.EqualFold(n, name) {
+			return b, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown provider %q. Only 'oidc', 'aws' and 'saml' are accepted", name)
+}
+
+// New returns the Provider implementation of a backend
+func New(b Backend) (Provider, error) {
+	switch b {
+	case OIDC:
+		return &oidc.Provider{}, nil
+	case AWS, SAML:
+		return nil, fmt.Errorf("provider '%s' is not supported yet", backendNames[b])
+	default:
+		return nil, fmt.Errorf("unknown provider backend %d", b)
+	}
+}
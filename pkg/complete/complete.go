@@ -0,0 +1,337 @@
+package complete
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/functions"
+	allFns "github.com/loicsikidi/wif-go/pkg/compiler/functions/all"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/attribute"
+	"github.com/loicsikidi/wif-go/pkg/token"
+	"github.com/loicsikidi/wif-go/pkg/token/inspect"
+)
+
+// Kinds of a suggestion.
+const (
+	KindVariable  = "variable"
+	KindClaim     = "claim"
+	KindAttribute = "attribute"
+	KindFunction  = "function"
+	KindMacro     = "macro"
+)
+
+// delimiters end the word being completed
+const delimiters = " \t\r\n()[]{},!&|=<>+-*/%?:"
+
+// maximumDetailLength is the maximum length of the value of a claim shown as detail
+const maximumDetailLength = 50
+
+// mapType is the CEL type of the variables and of the JSON objects
+const mapType = "map(string, dyn)"
+
+// standard are the CEL standard functions and macros, a signature starting with a receiver (eg. <string>.) is a member overload
+var standard = []*function{
+	{name: "all", kind: KindMacro, signatures: []string{"<list>.all(<var>, <bool>) -> <bool>", "<map>.all(<var>, <bool>) -> <bool>"}},
+	{name: "bool", kind: KindFunction, signatures: []string{"bool(<string>) -> <bool>"}},
+	{name: "bytes", kind: KindFunction, signatures: []string{"bytes(<string>) -> <bytes>"}},
+	{name: "contains", kind: KindFunction, signatures: []string{"<string>.contains(<string>) -> <bool>"}},
+	{name: "double", kind: KindFunction, signatures: []string{"double(<int>) -> <double>", "double(<string>) -> <double>"}},
+	{name: "duration", kind: KindFunction, signatures: []string{"duration(<string>) -> <google.protobuf.Duration>"}},
+	{name: "endsWith", kind: KindFunction, signatures: []string{"<string>.endsWith(<string>) -> <bool>"}},
+	{name: "exists", kind: KindMacro, signatures: []string{"<list>.exists(<var>, <bool>) -> <bool>", "<map>.exists(<var>, <bool>) -> <bool>"}},
+	{name: "exists_one", kind: KindMacro, signatures: []string{"<list>.exists_one(<var>, <bool>) -> <bool>", "<map>.exists_one(<var>, <bool>) -> <bool>"}},
+	{name: "filter", kind: KindMacro, signatures: []string{"<list>.filter(<var>, <bool>) -> <list>", "<map>.filter(<var>, <bool>) -> <list>"}},
+	{name: "has", kind: KindMacro, signatures: []string{"has(<map>.<field>) -> <bool>"}},
+	{name: "int", kind: KindFunction, signatures: []string{"int(<double>) -> <int>", "int(<string>) -> <int>"}},
+	{name: "map", kind: KindMacro, signatures: []string{"<list>.map(<var>, <dyn>) -> <list>", "<list>.map(<var>, <bool>, <dyn>) -> <list>"}},
+	{name: "matches", kind: KindFunction, signatures: []string{"<string>.matches(<string>) -> <bool>", "matches(<string>, <string>) -> <bool>"}},
+	{name: "size", kind: KindFunction, signatures: []string{"size(<string>) -> <int>", "size(<list>) -> <int>", "size(<map>) -> <int>", "<string>.size() -> <int>", "<list>.size() -> <int>", "<map>.size() -> <int>"}},
+	{name: "startsWith", kind: KindFunction, signatures: []string{"<string>.startsWith(<string>) -> <bool>"}},
+	{name: "string", kind: KindFunction, signatures: []string{"string(<int>) -> <string>", "string(<double>) -> <string>", "string(<bool>) -> <string>"}},
+	{name: "timestamp", kind: KindFunction, signatures: []string{"timestamp(<string>) -> <google.protobuf.Timestamp>"}},
+	{name: "type", kind: KindFunction, signatures: []string{"type(<dyn>) -> <type>"}},
+	{name: "uint", kind: KindFunction, signatures: []string{"uint(<int>) -> <uint>"}},
+}
+
+// function is a function or a macro which can be suggested
+type function struct {
+	name       string
+	kind       string
+	custom     bool
+	signatures []string
+}
+
+// overloads returns the global or the member signatures of the function
+func (f *function) overloads(member bool) []string {
+	var out []string
+	for _, s := range f.signatures {
+		if strings.HasPrefix(s, "<") == member {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (f *function) suggestion(label string, signatures []string) *Suggestion {
+	detail := "standard function"
+	switch {
+	case f.kind == KindMacro:
+		detail = "macro"
+	case f.custom:
+		detail = "custom function"
+	}
+	return &Suggestion{Label: label + "(", Kind: f.kind, Type: strings.Join(signatures, "\n"), Detail: detail}
+}
+
+// Suggestion completes the word at the cursor
+type Suggestion struct {
+	// Label replaces the word (eg. assertion.sub), functions and macros end with an opening parenthesis (eg. size().
+	Label string
+	// Kind of the suggestion (eg. KindClaim).
+	Kind string
+	// Type is the CEL type of a variable, a claim or an attribute (eg. string, list(string)),
+	// or the signatures of a function or a macro, one per line (eg. <string>.extract(<string>) -> <string>).
+	Type string
+	// Detail describes the suggestion (eg. the value of a claim).
+	Detail string
+}
+
+// Result of a completion
+type Result struct {
+	// From and To are the range of the word replaced by a suggestion, in characters of the text.
+	From int
+	To   int
+	// Suggestions sorted by label.
+	Suggestions []*Suggestion
+}
+
+// Completer completes the CEL expression of an attribute mapping or of an attribute condition
+type Completer struct {
+	// Claims of the token (cf. Claims), claim paths aren't suggested when it's empty.
+	Claims map[string]any
+	// AttributeMapping provides the custom attributes suggested in an attribute condition.
+	AttributeMapping map[string]string
+	// Condition completes an attribute condition, where 'google' and 'attribute' are available, rather than an attribute mapping.
+	Condition bool
+	// Workforce suggests the attributes of Workforce Identity Federation (cf. compiler.GoogleAttributes).
+	Workforce bool
+}
+
+// Claims returns the claims of a token, either a JWT or a JSON document holding the claims
+func Claims(raw string) (map[string]any, error) {
+	payload, err := token.Payload(raw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]any{}
+	if err := json.Unmarshal([]byte(payload), &claims); err != nil {
+		return nil, fmt.Errorf("the token claims must be a JSON object: %w", err)
+	}
+	return claims, nil
+}
+
+// Complete returns the suggestions completing the word at an offset of a text, offsets being counted in characters.
+// Nothing is suggested inside a string literal.
+func (c *Completer) Complete(text string, offset int) *Result {
+	runes := []rune(text)
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(runes) {
+		offset = len(runes)
+	}
+
+	res := &Result{From: offset, To: offset, Suggestions: []*Suggestion{}}
+	if inString(runes[:offset]) {
+		return res
+	}
+
+	for res.From > 0 && !strings.ContainsRune(delimiters, runes[res.From-1]) {
+		res.From--
+	}
+	for res.To < len(runes) && isWord(runes[res.To]) {
+		res.To++
+	}
+
+	word := string(runes[res.From:offset])
+	for _, s := range c.candidates(word) {
+		dotted := strings.NewReplacer("['", ".", "']", "").Replace(s.Label)
+		if strings.HasPrefix(s.Label, word) || strings.HasPrefix(dotted, word) {
+			res.Suggestions = append(res.Suggestions, s)
+		}
+	}
+
+	sort.SliceStable(res.Suggestions, func(i, j int) bool {
+		return res.Suggestions[i].Label < res.Suggestions[j].Label
+	})
+	return res
+}
+
+// candidates returns the suggestions which may complete a word
+func (c *Completer) candidates(word string) []*Suggestion {
+	var out []*Suggestion
+
+	dot := strings.LastIndex(word, ".")
+	if dot < 0 {
+		out = append(out, c.variables()...)
+		for _, f := range c.functions() {
+			if overloads := f.overloads(false); len(overloads) > 0 {
+				out = append(out, f.suggestion(f.name, overloads))
+			}
+		}
+		return out
+	}
+
+	root := word[:strings.Index(word, ".")]
+	if !c.isVariable(root) {
+		// 'google' and 'attribute' are undeclared in an attribute mapping
+		for _, v := range attribute.VARIABLES {
+			if v == root {
+				return nil
+			}
+		}
+	}
+
+	switch {
+	case root == attribute.Assertion:
+		out = append(out, claims(attribute.Assertion, c.Claims)...)
+	case root == attribute.Google && c.Condition && dot == len(root):
+		for _, k := range compiler.GoogleAttributes(c.Workforce) {
+			t := "string"
+			if k == compiler.GoogleGroups {
+				t = "list(string)"
+			}
+			out = append(out, &Suggestion{Label: k, Kind: KindAttribute, Type: t, Detail: c.mapped(k)})
+		}
+	case root == attribute.Attribute && c.Condition && dot == len(root):
+		for k := range c.AttributeMapping {
+			if strings.HasPrefix(k, attribute.Attribute+".") {
+				out = append(out, &Suggestion{Label: k, Kind: KindAttribute, Type: "string", Detail: c.mapped(k)})
+			}
+		}
+	}
+
+	// member functions (eg. assertion.sub.extract), the variables themselves aren't receivers
+	if receiver := word[:dot]; strings.Contains(receiver, ".") || !c.isVariable(receiver) {
+		for _, f := range c.functions() {
+			if overloads := f.overloads(true); len(overloads) > 0 {
+				out = append(out, f.suggestion(receiver+"."+f.name, overloads))
+			}
+		}
+	}
+	return out
+}
+
+// variables returns the variables of the expression: 'assertion' in an attribute mapping, 'google' and 'attribute' as well in an attribute condition
+func (c *Completer) variables() []*Suggestion {
+	out := []*Suggestion{{Label: attribute.Assertion, Kind: KindVariable, Type: mapType, Detail: "claims of the token"}}
+	if c.Condition {
+		out = append(out,
+			&Suggestion{Label: attribute.Google, Kind: KindVariable, Type: mapType, Detail: "Google attributes derived by the attribute mapping"},
+			&Suggestion{Label: attribute.Attribute, Kind: KindVariable, Type: mapType, Detail: "custom attributes derived by the attribute mapping"},
+		)
+	}
+	return out
+}
+
+func (c *Completer) isVariable(name string) bool {
+	for _, v := range c.variables() {
+		if v.Label == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mapped describes how an attribute is mapped
+func (c *Completer) mapped(key string) string {
+	if expr, ok := c.AttributeMapping[key]; ok {
+		return "mapped to " + expr
+	}
+	return "not mapped"
+}
+
+// functions returns the CEL standard functions, the macros and the registered custom functions
+func (c *Completer) functions() []*function {
+	out := append([]*function{}, standard...)
+	for name, fn := range allFns.ProvideAll() {
+		f := &function{name: name, kind: KindFunction, custom: true, signatures: []string{"<dyn>." + name + "(...) -> <dyn>"}}
+		if d, ok := fn.(functions.Documented); ok {
+			f.signatures = d.Signatures()
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// claims returns the suggestions of every claim (nested ones included) with their type
+func claims(root string, values map[string]any) []*Suggestion {
+	var out []*Suggestion
+	for name, value := range values {
+		path := inspect.Path(root, name)
+		out = append(out, &Suggestion{Label: path, Kind: KindClaim, Type: typeOf(value), Detail: preview(value)})
+
+		if nested, ok := value.(map[string]any); ok {
+			out = append(out, claims(path, nested)...)
+		}
+	}
+	return out
+}
+
+// typeOf returns the CEL type of a JSON value (numbers are doubles)
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null_type"
+	case bool:
+		return "bool"
+	case float64, json.Number:
+		return "double"
+	case string:
+		return "string"
+	case []any:
+		return "list(dyn)"
+	case map[string]any:
+		return mapType
+	default:
+		return "dyn"
+	}
+}
+
+// preview returns the JSON value of a claim, truncated to maximumDetailLength characters
+func preview(value any) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	if runes := []rune(string(out)); len(runes) > maximumDetailLength {
+		return string(runes[:maximumDetailLength-1]) + "…"
+	}
+	return string(out)
+}
+
+// inString tells whether the end of a text is inside a string literal
+func inString(runes []rune) bool {
+	var quote rune
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case quote == 0 && (r == '\'' || r == '"'):
+			quote = r
+		case quote != 0 && r == '\\':
+			i++
+		case r == quote:
+			quote = 0
+		}
+	}
+	return quote != 0
+}
+
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
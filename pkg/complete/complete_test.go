@@ -0,0 +1,148 @@
+package complete
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	claims, err := Claims(`{"sub": "repo:octo-org/octo-repo", "groups": ["admins"], "iat": 1700000000, "https://example.com/role": "admin", "org": {"name": "octo-org"}}`)
+
+	if err != nil {
+		t.Fatalf("Claims() = %s, expected no error", err)
+	}
+
+	mapping := map[string]string{"google.subject": "assertion.sub", "attribute.org": "assertion.org.name"}
+
+	type Expected struct {
+		label string
+		kind  string
+		typ   string
+	}
+	tests := []struct {
+		text      string
+		offset    int
+		condition bool
+		from      int
+		to        int
+		expected  []*Expected
+	}{
+		{
+			text:   "assertion.",
+			offset: 10,
+			to:     10,
+			expected: []*Expected{
+				{label: "assertion.groups", kind: KindClaim, typ: "list(dyn)"},
+				{label: "assertion.iat", kind: KindClaim, typ: "double"},
+				{label: "assertion.org", kind: KindClaim, typ: "map(string, dyn)"},
+				{label: "assertion.org.name", kind: KindClaim, typ: "string"},
+				{label: "assertion.sub", kind: KindClaim, typ: "string"},
+				{label: "assertion['https://example.com/role']", kind: KindClaim, typ: "string"},
+			},
+		},
+		// the word after the offset is replaced
+		{
+			text:   "'admins' in assertion.grxx",
+			offset: 24,
+			from:   12,
+			to:     26,
+			expected: []*Expected{
+				{label: "assertion.groups", kind: KindClaim, typ: "list(dyn)"},
+			},
+		},
+		{
+			text:   "assertion.h",
+			offset: 11,
+			to:     11,
+			expected: []*Expected{
+				{label: "assertion['https://example.com/role']", kind: KindClaim, typ: "string"},
+			},
+		},
+		{
+			text:      "google.",
+			offset:    7,
+			condition: true,
+			to:        7,
+			expected: []*Expected{
+				{label: "google.groups", kind: KindAttribute, typ: "list(string)"},
+				{label: "google.subject", kind: KindAttribute, typ: "string"},
+			},
+		},
+		// 'google' and 'attribute' are only available in an attribute condition
+		{
+			text:   "google.",
+			offset: 7,
+			to:     7,
+		},
+		{
+			text:      "attribute.org == 'octo' && attribute.",
+			offset:    37,
+			condition: true,
+			from:      27,
+			to:        37,
+			expected: []*Expected{
+				{label: "attribute.org", kind: KindAttribute, typ: "string"},
+			},
+		},
+		{
+			text:   "assertion.sub.ex",
+			offset: 16,
+			to:     16,
+			expected: []*Expected{
+				{label: "assertion.sub.exists(", kind: KindMacro, typ: "<list>.exists(<var>, <bool>) -> <bool>\n<map>.exists(<var>, <bool>) -> <bool>"},
+				{label: "assertion.sub.exists_one(", kind: KindMacro, typ: "<list>.exists_one(<var>, <bool>) -> <bool>\n<map>.exists_one(<var>, <bool>) -> <bool>"},
+				{label: "assertion.sub.extract(", kind: KindFunction, typ: "<string>.extract(<string>) -> <string>"},
+			},
+		},
+		{
+			text:      "ha",
+			offset:    2,
+			condition: true,
+			to:        2,
+			expected: []*Expected{
+				{label: "has(", kind: KindMacro, typ: "has(<map>.<field>) -> <bool>"},
+			},
+		},
+		{
+			text:   "s",
+			offset: 1,
+			to:     1,
+			expected: []*Expected{
+				{label: "size(", kind: KindFunction, typ: "size(<string>) -> <int>\nsize(<list>) -> <int>\nsize(<map>) -> <int>"},
+				{label: "string(", kind: KindFunction, typ: "string(<int>) -> <string>\nstring(<double>) -> <string>\nstring(<bool>) -> <string>"},
+			},
+		},
+		// nothing is suggested in a string literal
+		{
+			text:   "assertion.sub == 'assertion.",
+			offset: 28,
+			from:   28,
+			to:     28,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			c := &Completer{Claims: claims, AttributeMapping: mapping, Condition: tc.condition}
+			res := c.Complete(tc.text, tc.offset)
+
+			if res.From != tc.from || res.To != tc.to {
+				t.Fatalf("Complete() = [%d, %d], expected [%d, %d]", res.From, res.To, tc.from, tc.to)
+			}
+
+			var got []*Expected
+			for _, s := range res.Suggestions {
+				got = append(got, &Expected{label: s.Label, kind: s.Kind, typ: s.Type})
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				for _, s := range res.Suggestions {
+					t.Logf("%+v", s)
+				}
+				t.Fatalf("Complete(%s) = %d suggestions, expected %d", tc.text, len(got), len(tc.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package config loads the configuration of a provider evaluated by the compiler
+// (ie. its type, attribute mapping and attribute condition) from a Terraform file or from its REST representation.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/rest"
+	"github.com/loicsikidi/wif-go/pkg/terraform"
+)
+
+// Provider is the configuration of a provider evaluated by the compiler
+type Provider struct {
+	// Backend of the provider (eg. provider.OIDC).
+	Backend provider.Backend
+	// AttributeMapping of the provider.
+	AttributeMapping map[string]string
+	// AttributeCondition of the provider.
+	AttributeCondition string
+}
+
+// Load reads a provider configuration, either from a Terraform file (.tf)
+// or from its REST representation (JSON or YAML).
+// The resource name (eg. github) is required when a Terraform file declares several providers.
+func Load(path, resourceName string) (*Provider, error) {
+	if filepath.Ext(path) == ".tf" {
+		return FromTerraform(path, resourceName)
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := rest.ParseProvider(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return FromREST(p), nil
+}
+
+// FromTerraform reads the provider declared by a Terraform file whose address (or name, eg. github) is the resource name.
+// The resource name may be empty when the file declares a single provider.
+func FromTerraform(path, resourceName string) (*Provider, error) {
+	providers, err := terraform.ParseFiles(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*terraform.Provider
+	for _, tf := range providers {
+		if resourceName == "" || tf.Address == resourceName || strings.HasSuffix(tf.Address, "."+resourceName) {
+			matches = append(matches, tf)
+		}
+	}
+
+	if len(matches) != 1 {
+		return nil, fmt.Errorf("%s: expected exactly one provider matching %q, found %d", path, resourceName, len(matches))
+	}
+
+	r := matches[0].Resource
+	return &Provider{Backend: r.Type, AttributeMapping: r.AttributeMapping, AttributeCondition: r.AttributeCondition}, nil
+}
+
+// FromREST returns the configuration of the REST representation of a provider (oidc by default)
+func FromREST(p *rest.Provider) *Provider {
+	c := &Provider{Backend: provider.OIDC, AttributeMapping: p.AttributeMapping, AttributeCondition: p.AttributeCondition}
+	switch {
+	case p.AWS != nil:
+		c.Backend = provider.AWS
+	case p.SAML != nil:
+		c.Backend = provider.SAML
+	}
+	return c
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+const tfProvider = `resource "google_iam_workload_identity_pool_provider" "%s" {
+  workload_identity_pool_provider_id = "%s"
+  attribute_mapping = { "google.subject" = "assertion.sub" }
+  oidc { issuer_uri = "https://token.actions.githubusercontent.com" }
+}
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"oidc.yaml":     "attributeMapping:\n  google.subject: assertion.sub\nattributeCondition: 'true'\n",
+		"aws.json":      `{"attributeMapping": {"google.subject": "assertion.arn"}, "aws": {"accountId": "123456789012"}}`,
+		"saml.yaml":     "attributeMapping:\n  google.subject: assertion.subject\nsaml:\n  idpMetadataXml: '<xml/>'\n",
+		"invalid.json":  `{"attributeMapping": ["google.subject"]}`,
+		"backends.json": `{"attributeMapping": {"google.subject": "assertion.sub"}, "oidc": {"issuerUri": "https://example.com"}, "saml": {"idpMetadataXml": "<xml/>"}}`,
+		"main.tf":       fmt.Sprintf(tfProvider, "github", "github"),
+		"several.tf":    fmt.Sprintf(tfProvider, "github", "github") + fmt.Sprintf(tfProvider, "gitlab", "gitlab"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path      string
+		resource  string
+		expected  *Provider
+		exception bool
+	}{
+		{path: "oidc.yaml", expected: &Provider{Backend: provider.OIDC, AttributeCondition: "true"}},
+		{path: "aws.json", expected: &Provider{Backend: provider.AWS}},
+		{path: "saml.yaml", expected: &Provider{Backend: provider.SAML}},
+		{path: "main.tf", expected: &Provider{Backend: provider.OIDC}},
+		{path: "several.tf", resource: "gitlab", expected: &Provider{Backend: provider.OIDC}},
+		{path: "several.tf", resource: "google_iam_workload_identity_pool_provider.github", expected: &Provider{Backend: provider.OIDC}},
+		// invalid configurations
+		{path: "several.tf", exception: true},
+		{path: "main.tf", resource: "gitlab", exception: true},
+		{path: "invalid.json", exception: true},
+		{path: "backends.json", exception: true},
+		{path: "missing.yaml", exception: true},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			path := filepath.Join(dir, tc.path)
+
+			c, err := Load(path, tc.resource)
+
+			if tc.exception {
+				if err == nil {
+					t.Fatalf("Load(%s, %q) -> expect exception", path, tc.resource)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load(%s, %q) = %s, expected no error", path, tc.resource, err)
+			}
+			if c.Backend != tc.expected.Backend || c.AttributeCondition != tc.expected.AttributeCondition || len(c.AttributeMapping) != 1 {
+				t.Fatalf("Load(%s, %q) = %+v, expected %+v", path, tc.resource, c, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package coverage
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/parser"
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/attribute"
+	"github.com/loicsikidi/wif-go/pkg/format"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// Kinds of branch.
+const (
+	// KindOperand is an operand of '&&' or '||'.
+	KindOperand = "operand"
+	// KindTernaryTrue is the arm of a ternary taken when the condition is true.
+	KindTernaryTrue = "ternary-true"
+	// KindTernaryFalse is the arm of a ternary taken when the condition is false.
+	KindTernaryFalse = "ternary-false"
+	// KindMapKey is a key of a map literal used as a lookup table (eg. {'admins': 'admin'}[assertion.role]).
+	KindMapKey = "map-key"
+)
+
+// Branch is a part of the attribute condition which must be exercised by tests
+type Branch struct {
+	// Kind of branch (eg. KindOperand).
+	Kind string
+	// Expression is the source of the branch (or the key of a lookup table).
+	Expression string
+	// Line of the branch in the attribute condition (starting at 1).
+	Line int
+	// Column of the branch in the attribute condition (starting at 1).
+	Column int
+	// Accepted is the number of accepted credentials exercising the branch.
+	Accepted int
+	// Rejected is the number of rejected credentials exercising the branch.
+	Rejected int
+
+	id  int64
+	key ref.Val
+}
+
+// Covered tells whether the branch was exercised by at least one accepted and one rejected credential
+func (b *Branch) Covered() bool {
+	return b.Accepted > 0 && b.Rejected > 0
+}
+
+// Report is the coverage of an attribute condition
+type Report struct {
+	// Condition is the attribute condition.
+	Condition string
+	// Branches of the condition, in the source order.
+	Branches []*Branch
+	// Accepted is the number of accepted credentials.
+	Accepted int
+	// Rejected is the number of rejected credentials.
+	Rejected int
+	// Errors is the number of credentials which couldn't be evaluated.
+	Errors int
+}
+
+// Covered returns the number of covered branches
+func (r *Report) Covered() int {
+	covered := 0
+	for _, b := range r.Branches {
+		if b.Covered() {
+			covered++
+		}
+	}
+	return covered
+}
+
+// Percent returns the percentage of covered branches (100 when the condition has no branch)
+func (r *Report) Percent() float64 {
+	if len(r.Branches) == 0 {
+		return 100
+	}
+	return float64(r.Covered()) * 100 / float64(len(r.Branches))
+}
+
+// Tracker records which branches of an attribute condition are exercised by credentials
+type Tracker struct {
+	report  *Report
+	program cel.Program
+}
+
+// New returns a tracker of an attribute condition
+func New(condition string) (*Tracker, error) {
+	parsed, err := format.Parse(condition)
+
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := compiler.NewEnv(&attribute.Provider{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the checker keeps the ids of the parsed expression, so branches can be looked up in the evaluation state
+	ast, issues := env.Check(cel.ParsedExprToAst(parsed))
+
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("error compiling CEL expression: %w", issues.Err())
+	}
+
+	program, err := env.Program(ast, cel.EvalOptions(cel.OptTrackState))
+
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tracker{report: &Report{Condition: condition}, program: program}
+	w := &walker{info: parsed.GetSourceInfo(), source: common.NewTextSource(condition)}
+	w.walk(parsed.GetExpr())
+	t.report.Branches = w.branches
+	return t, nil
+}
+
+// walker collects the branches of an expression
+type walker struct {
+	info     *exprpb.SourceInfo
+	source   common.Source
+	branches []*Branch
+}
+
+func (w *walker) add(kind string, e *exprpb.Expr, expression string) *Branch {
+	if expression == "" {
+		expression, _ = parser.Unparse(e, w.info)
+	}
+
+	b := &Branch{Kind: kind, Expression: expression, id: e.GetId()}
+	if loc, ok := w.source.OffsetLocation(w.start(e)); ok {
+		b.Line, b.Column = loc.Line(), loc.Column()+1
+	}
+	w.branches = append(w.branches, b)
+	return b
+}
+
+// start returns the offset of the first character of an expression.
+// The position of a call is the one of its operator (eg. '=='), so the positions of its arguments are considered.
+func (w *walker) start(e *exprpb.Expr) int32 {
+	offset := w.info.GetPositions()[e.GetId()]
+
+	var children []*exprpb.Expr
+	switch k := e.GetExprKind().(type) {
+	case *exprpb.Expr_SelectExpr:
+		children = append(children, k.SelectExpr.GetOperand())
+	case *exprpb.Expr_CallExpr:
+		children = append(children, k.CallExpr.GetTarget())
+		children = append(children, k.CallExpr.GetArgs()...)
+	}
+
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if o := w.start(child); o < offset {
+			offset = o
+		}
+	}
+	return offset
+}
+
+// operands flattens nested calls of the same logical operator (eg. a && b && c)
+func operands(op string, e *exprpb.Expr) []*exprpb.Expr {
+	call := e.GetCallExpr()
+	if call == nil || call.GetFunction() != op {
+		return []*exprpb.Expr{e}
+	}
+
+	var out []*exprpb.Expr
+	for _, arg := range call.GetArgs() {
+		out = append(out, operands(op, arg)...)
+	}
+	return out
+}
+
+func (w *walker) walk(e *exprpb.Expr) {
+	if e == nil {
+		return
+	}
+
+	switch k := e.GetExprKind().(type) {
+	case *exprpb.Expr_SelectExpr:
+		w.walk(k.SelectExpr.GetOperand())
+	case *exprpb.Expr_CallExpr:
+		call := k.CallExpr
+
+		switch fn := call.GetFunction(); fn {
+		case operators.LogicalAnd, operators.LogicalOr:
+			for _, operand := range operands(fn, e) {
+				w.add(KindOperand, operand, "")
+				w.walk(operand)
+			}
+			return
+		case operators.Conditional:
+			w.walk(call.GetArgs()[0])
+			w.add(KindTernaryTrue, call.GetArgs()[1], "")
+			w.walk(call.GetArgs()[1])
+			w.add(KindTernaryFalse, call.GetArgs()[2], "")
+			w.walk(call.GetArgs()[2])
+			return
+		case operators.Index:
+			if entries := mapLiteral(call.GetArgs()[0]); entries != nil {
+				for _, entry := range entries {
+					key := entry.GetMapKey()
+					text, _ := parser.Unparse(key, w.info)
+					b := w.add(KindMapKey, key, "key "+text)
+					// the branch is exercised when the index evaluates to the key
+					b.id = call.GetArgs()[1].GetId()
+					b.key = constant(key)
+				}
+			}
+		}
+
+		w.walk(call.GetTarget())
+		for _, arg := range call.GetArgs() {
+			w.walk(arg)
+		}
+	case *exprpb.Expr_ListExpr:
+		for _, elem := range k.ListExpr.GetElements() {
+			w.walk(elem)
+		}
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range k.StructExpr.GetEntries() {
+			w.walk(entry.GetMapKey())
+			w.walk(entry.GetValue())
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		c := k.ComprehensionExpr
+		w.walk(c.GetIterRange())
+		w.walk(c.GetLoopStep())
+		w.walk(c.GetResult())
+	}
+}
+
+// mapLiteral returns the entries of a map literal whose keys are constants
+func mapLiteral(e *exprpb.Expr) []*exprpb.Expr_CreateStruct_Entry {
+	s := e.GetStructExpr()
+	if s == nil || s.GetMessageName() != "" {
+		return nil
+	}
+
+	for _, entry := range s.GetEntries() {
+		if constant(entry.GetMapKey()) == nil {
+			return nil
+		}
+	}
+	return s.GetEntries()
+}
+
+// constant returns the value of a constant expression, nil otherwise
+func constant(e *exprpb.Expr) ref.Val {
+	c := e.GetConstExpr()
+	if c == nil {
+		return nil
+	}
+
+	switch v := c.GetConstantKind().(type) {
+	case *exprpb.Constant_StringValue:
+		return types.String(v.StringValue)
+	case *exprpb.Constant_Int64Value:
+		return types.Int(v.Int64Value)
+	case *exprpb.Constant_Uint64Value:
+		return types.Uint(v.Uint64Value)
+	case *exprpb.Constant_BoolValue:
+		return types.Bool(v.BoolValue)
+	default:
+		return nil
+	}
+}
+
+// Observe evaluates the attribute condition against a credential and records the branches it exercises.
+// The attribute mapping, the provider and the workforce flag of the compiler are used to derive the attributes,
+// its attribute condition is ignored.
+func (t *Tracker) Observe(c *compiler.Compiler) error {
+	if c.Input == nil {
+		return fmt.Errorf("input is invalid. Payload and AttributeMapping are required")
+	}
+
+	mapping := &compiler.Compiler{
+		Input:     &compiler.Input{Payload: c.Input.Payload, AttributeMapping: c.Input.AttributeMapping},
+		Provider:  c.Provider,
+		Workforce: c.Workforce,
+	}
+
+	attributes, err := mapping.Run()
+
+	if err != nil {
+		t.report.Errors++
+		return err
+	}
+
+	input, err := c.Provider.GetInputVar(c.Input.Payload)
+
+	if err != nil {
+		t.report.Errors++
+		return err
+	}
+
+	conditionInput, err := compiler.ConditionInput(input, attributes)
+
+	if err != nil {
+		t.report.Errors++
+		return err
+	}
+
+	out, details, err := t.program.Eval(conditionInput)
+
+	if err != nil {
+		t.report.Errors++
+		return fmt.Errorf("error evaluating CEL expression: %w", err)
+	}
+
+	accepted := out == types.True
+	if accepted {
+		t.report.Accepted++
+	} else {
+		t.report.Rejected++
+	}
+
+	state := details.State()
+	for _, b := range t.report.Branches {
+		val, ok := state.Value(b.id)
+
+		if !ok || (b.key != nil && b.key.Equal(val) != types.True) {
+			continue
+		}
+
+		if accepted {
+			b.Accepted++
+		} else {
+			b.Rejected++
+		}
+	}
+	return nil
+}
+
+// Report returns the coverage recorded so far
+func (t *Tracker) Report() *Report {
+	return t.report
+}
@@ -0,0 +1,162 @@
+package coverage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/oidc"
+)
+
+func observe(t *testing.T, tracker *Tracker, payloads ...string) {
+	t.Helper()
+
+	for _, payload := range payloads {
+		c := &compiler.Compiler{
+			Input: &compiler.Input{
+				Payload:          payload,
+				AttributeMapping: map[string]string{"google.subject": "assertion.sub", "attribute.role": "assertion.role"},
+			},
+			Provider: &oidc.Provider{},
+		}
+
+		if err := tracker.Observe(c); err != nil {
+			t.Fatalf("Observe(%s) = %s, expected no error", payload, err)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	type Expected struct {
+		kind       string
+		expression string
+		line       int
+		column     int
+		accepted   int
+		rejected   int
+	}
+	tests := []struct {
+		condition string
+		payloads  []string
+		expected  []*Expected
+	}{
+		{
+			condition: "assertion.owner == 'octo-org' &&\n  (assertion.ref == 'refs/heads/main' || attribute.role == 'admin')",
+			payloads: []string{
+				`{"sub": "a", "owner": "octo-org", "ref": "refs/heads/main", "role": "dev"}`,
+				`{"sub": "b", "owner": "evil-org", "ref": "refs/heads/main", "role": "dev"}`,
+				`{"sub": "c", "owner": "octo-org", "ref": "refs/heads/dev", "role": "dev"}`,
+			},
+			expected: []*Expected{
+				{kind: KindOperand, expression: `assertion.owner == "octo-org"`, line: 1, column: 1, accepted: 1, rejected: 2},
+				{kind: KindOperand, expression: `assertion.ref == "refs/heads/main" || attribute.role == "admin"`, line: 2, column: 4, accepted: 1, rejected: 1},
+				{kind: KindOperand, expression: `assertion.ref == "refs/heads/main"`, line: 2, column: 4, accepted: 1, rejected: 1},
+				{kind: KindOperand, expression: `attribute.role == "admin"`, line: 2, column: 42, rejected: 1},
+			},
+		},
+		{
+			condition: "assertion.owner == 'octo-org' ? attribute.role != 'guest' : false",
+			payloads: []string{
+				`{"sub": "a", "owner": "octo-org", "role": "dev"}`,
+				`{"sub": "b", "owner": "octo-org", "role": "guest"}`,
+			},
+			expected: []*Expected{
+				{kind: KindTernaryTrue, expression: `attribute.role != "guest"`, line: 1, column: 33, accepted: 1, rejected: 1},
+				{kind: KindTernaryFalse, expression: `false`, line: 1, column: 61},
+			},
+		},
+		{
+			condition: "{'admin': true, 'dev': true, 'guest': false}[attribute.role]",
+			payloads: []string{
+				`{"sub": "a", "role": "admin"}`,
+				`{"sub": "b", "role": "guest"}`,
+			},
+			expected: []*Expected{
+				{kind: KindMapKey, expression: `key "admin"`, line: 1, column: 2, accepted: 1},
+				{kind: KindMapKey, expression: `key "dev"`, line: 1, column: 17},
+				{kind: KindMapKey, expression: `key "guest"`, line: 1, column: 30, rejected: 1},
+			},
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			tracker, err := New(tc.condition)
+
+			if err != nil {
+				t.Fatalf("New(%s) = %s, expected no error", tc.condition, err)
+			}
+
+			observe(t, tracker, tc.payloads...)
+			report := tracker.Report()
+
+			if len(report.Branches) != len(tc.expected) {
+				t.Fatalf("Report() = %d branches, expected %d", len(report.Branches), len(tc.expected))
+			}
+
+			for j, b := range report.Branches {
+				got := &Expected{kind: b.Kind, expression: b.Expression, line: b.Line, column: b.Column, accepted: b.Accepted, rejected: b.Rejected}
+				if *got != *tc.expected[j] {
+					t.Fatalf("branch [%d] = %+v, expected %+v", j, got, tc.expected[j])
+				}
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tracker, _ := New("assertion.sub == 'a' || assertion.sub == 'b'")
+	observe(t, tracker, `{"sub": "a", "role": "dev"}`, `{"sub": "c", "role": "dev"}`)
+
+	// assertion.sub == 'b' is never evaluated by an accepted credential
+	report := tracker.Report()
+	if report.Covered() != 1 || report.Percent() != 50 || report.Accepted != 1 || report.Rejected != 1 {
+		t.Fatalf("Report() = %d covered (%.0f%%), expected 1 (50%%)", report.Covered(), report.Percent())
+	}
+
+	if _, err := New("assertion.sub =="); err == nil {
+		t.Fatalf("New() -> expect exception")
+	}
+}
+
+func TestObserveWorkforce(t *testing.T) {
+	tests := []struct {
+		workforce bool
+		exception bool
+	}{
+		{workforce: true},
+		// google.display_name is only available with Workforce Identity Federation
+		{workforce: false, exception: true},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			tracker, _ := New("attribute.role == 'admin'")
+			c := &compiler.Compiler{
+				Input: &compiler.Input{
+					Payload:          `{"sub": "a", "name": "Alice", "role": "admin"}`,
+					AttributeMapping: map[string]string{"google.subject": "assertion.sub", "google.display_name": "assertion.name", "attribute.role": "assertion.role"},
+				},
+				Provider:  &oidc.Provider{},
+				Workforce: tc.workforce,
+			}
+
+			err := tracker.Observe(c)
+
+			if tc.exception {
+				if err == nil {
+					t.Fatalf("Observe() -> expect exception")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Observe() = %s, expected no error", err)
+			}
+			if report := tracker.Report(); report.Accepted != 1 {
+				t.Fatalf("Report() = %d accepted, expected 1", report.Accepted)
+			}
+		})
+	}
+}
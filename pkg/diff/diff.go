@@ -0,0 +1,230 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/config"
+	"github.com/loicsikidi/wif-go/pkg/token"
+)
+
+// Statuses of an outcome.
+const (
+	StatusAccepted = "accepted"
+	StatusRejected = "rejected"
+	StatusError    = "error"
+)
+
+// LoadConfig reads a provider configuration compared by Run (cf. config.Load)
+// and rejects a configuration without attribute mapping (eg. a file with the wrong keys),
+// every token would be an error with both configurations, hiding their differences
+func LoadConfig(path, resourceName string) (*config.Provider, error) {
+	c, err := config.Load(path, resourceName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.AttributeMapping) == 0 {
+		return nil, fmt.Errorf("%s: the attribute mapping is required", path)
+	}
+	if _, ok := c.AttributeMapping[compiler.GoogleSubject]; !ok {
+		return nil, fmt.Errorf("%s: missing '%s' attribute in the attribute mapping", path, compiler.GoogleSubject)
+	}
+	return c, nil
+}
+
+// Token is a token of the corpus
+type Token struct {
+	// Name of the token (ie. its path relative to the corpus).
+	Name string
+	// Payload holds the JSON claims of the token.
+	Payload string
+}
+
+// LoadCorpus reads every token (JWT or JSON claims) of a directory and its subdirectories,
+// hidden files are skipped
+func LoadCorpus(dir string) ([]*Token, error) {
+	var tokens []*Token
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+
+		if err != nil {
+			return err
+		}
+
+		payload, err := token.Payload(string(data))
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		name, _ := filepath.Rel(dir, path)
+		tokens = append(tokens, &Token{Name: name, Payload: payload})
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no token found in %s", dir)
+	}
+	return tokens, nil
+}
+
+// Outcome is the evaluation of a token against a configuration
+type Outcome struct {
+	// Status is either StatusAccepted, StatusRejected or StatusError.
+	Status string
+	// Attributes are the derived attributes (nil unless accepted).
+	Attributes map[string]any
+	// Err is the error returned by the compiler.
+	Err error
+}
+
+// AttributeChange is an attribute whose value differs between both configurations
+type AttributeChange struct {
+	// Name of the attribute (eg. google.subject).
+	Name string
+	// Old value (nil if the attribute wasn't derived).
+	Old any
+	// New value (nil if the attribute isn't derived anymore).
+	New any
+}
+
+// Change is the difference of outcome of a token
+type Change struct {
+	// Token evaluated.
+	Token *Token
+	// Old is the outcome with the old configuration.
+	Old *Outcome
+	// New is the outcome with the new configuration.
+	New *Outcome
+	// Attributes lists the derived attributes that changed when the token is accepted by both configurations.
+	Attributes []*AttributeChange
+}
+
+// Flipped tells whether the status changed (eg. accepted -> rejected)
+func (c *Change) Flipped() bool {
+	return c.Old.Status != c.New.Status
+}
+
+// ErrorChanged tells whether both configurations fail with a different error (ie. another category or message)
+func (c *Change) ErrorChanged() bool {
+	if c.Old.Status != StatusError || c.New.Status != StatusError {
+		return false
+	}
+	return compiler.ErrorCategory(c.Old.Err) != compiler.ErrorCategory(c.New.Err) || c.Old.Err.Error() != c.New.Err.Error()
+}
+
+// Report is the behavioral difference between two configurations
+type Report struct {
+	// Tokens is the number of tokens evaluated.
+	Tokens int
+	// Changes lists the tokens whose outcome changed (including the error of a token rejected by both configurations), in the corpus order.
+	Changes []*Change
+	// Gained is the number of tokens accepted by the new configuration only.
+	Gained int
+	// Lost is the number of tokens accepted by the old configuration only.
+	Lost int
+}
+
+func evaluate(c *config.Provider, t *Token) *Outcome {
+	p, err := provider.New(c.Backend)
+
+	if err != nil {
+		return &Outcome{Status: StatusError, Err: err}
+	}
+
+	comp := &compiler.Compiler{
+		Input: &compiler.Input{
+			Payload:            t.Payload,
+			AttributeMapping:   c.AttributeMapping,
+			AttributeCondition: c.AttributeCondition,
+		},
+		Provider: p,
+	}
+
+	attributes, err := comp.Run()
+
+	switch {
+	case err == nil:
+		return &Outcome{Status: StatusAccepted, Attributes: attributes}
+	case errors.Is(err, compiler.ErrAttrConditionFailed):
+		return &Outcome{Status: StatusRejected, Err: err}
+	default:
+		return &Outcome{Status: StatusError, Err: err}
+	}
+}
+
+// Run evaluates every token against both configurations and reports the tokens whose outcome changed
+func Run(oldConfig, newConfig *config.Provider, tokens []*Token) *Report {
+	report := &Report{Tokens: len(tokens)}
+
+	for _, t := range tokens {
+		c := &Change{Token: t, Old: evaluate(oldConfig, t), New: evaluate(newConfig, t)}
+
+		if c.Old.Status == StatusAccepted && c.New.Status == StatusAccepted {
+			c.Attributes = diffAttributes(c.Old.Attributes, c.New.Attributes)
+		}
+
+		switch {
+		case c.Old.Status != StatusAccepted && c.New.Status == StatusAccepted:
+			report.Gained++
+		case c.Old.Status == StatusAccepted && c.New.Status != StatusAccepted:
+			report.Lost++
+		}
+
+		if c.Flipped() || len(c.Attributes) > 0 || c.ErrorChanged() {
+			report.Changes = append(report.Changes, c)
+		}
+	}
+	return report
+}
+
+func diffAttributes(before, after map[string]any) []*AttributeChange {
+	names := map[string]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []*AttributeChange
+	for _, name := range sorted {
+		if !reflect.DeepEqual(before[name], after[name]) {
+			changes = append(changes, &AttributeChange{Name: name, Old: before[name], New: after[name]})
+		}
+	}
+	return changes
+}
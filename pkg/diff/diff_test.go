@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/config"
+)
+
+func TestRun(t *testing.T) {
+	oldConfig := &config.Provider{
+		Backend:            provider.OIDC,
+		AttributeMapping:   map[string]string{"google.subject": "assertion.sub"},
+		AttributeCondition: "assertion.repository_owner == 'octo-org'",
+	}
+	newConfig := &config.Provider{
+		Backend:            provider.OIDC,
+		AttributeMapping:   map[string]string{"google.subject": "assertion.sub", "attribute.owner": "assertion.repository_owner"},
+		AttributeCondition: "assertion.repository_owner in ['octo-org', 'partner-org'] && assertion.ref == 'refs/heads/main'",
+	}
+
+	tokens := []*Token{
+		{Name: "main", Payload: `{"sub": "main", "repository_owner": "octo-org", "ref": "refs/heads/main"}`},
+		{Name: "branch", Payload: `{"sub": "branch", "repository_owner": "octo-org", "ref": "refs/heads/dev"}`},
+		{Name: "partner", Payload: `{"sub": "partner", "repository_owner": "partner-org", "ref": "refs/heads/main"}`},
+		{Name: "evil", Payload: `{"sub": "evil", "repository_owner": "evil-org", "ref": "refs/heads/main"}`},
+		{Name: "broken", Payload: `{"repository_owner": "octo-org", "ref": "refs/heads/main"}`},
+	}
+
+	report := Run(oldConfig, newConfig, tokens)
+
+	if report.Tokens != 5 || report.Gained != 1 || report.Lost != 1 {
+		t.Fatalf("Run() = %+v, expected 5 tokens, 1 gained and 1 lost", report)
+	}
+
+	expected := []struct {
+		name       string
+		old, new   string
+		attributes int
+	}{
+		{name: "main", old: StatusAccepted, new: StatusAccepted, attributes: 1},
+		{name: "branch", old: StatusAccepted, new: StatusRejected},
+		{name: "partner", old: StatusRejected, new: StatusAccepted},
+	}
+
+	if len(report.Changes) != len(expected) {
+		t.Fatalf("Run() = %d changes, expected %d", len(report.Changes), len(expected))
+	}
+
+	for i, e := range expected {
+		c := report.Changes[i]
+		if c.Token.Name != e.name || c.Old.Status != e.old || c.New.Status != e.new || len(c.Attributes) != e.attributes {
+			t.Fatalf("change [%d] = %s %s -> %s (%d attributes), expected %+v", i, c.Token.Name, c.Old.Status, c.New.Status, len(c.Attributes), e)
+		}
+	}
+}
+
+func TestRunErrorChanged(t *testing.T) {
+	oldConfig := &config.Provider{Backend: provider.OIDC, AttributeMapping: map[string]string{"google.subject": "assertion.sub"}}
+	newConfig := &config.Provider{Backend: provider.OIDC, AttributeMapping: map[string]string{"google.subject": "assertion.subject"}}
+
+	tokens := []*Token{
+		{Name: "valid", Payload: `{"sub": "valid", "subject": "valid"}`},
+		{Name: "broken", Payload: `{"repository_owner": "octo-org"}`},
+		{Name: "invalid", Payload: `not a token`},
+	}
+
+	report := Run(oldConfig, newConfig, tokens)
+
+	if len(report.Changes) != 1 {
+		t.Fatalf("Run() = %d changes, expected 1", len(report.Changes))
+	}
+
+	c := report.Changes[0]
+	if c.Token.Name != "broken" || c.Flipped() || !c.ErrorChanged() {
+		t.Fatalf("change = %s %s -> %s, expected broken with another error", c.Token.Name, c.Old.Status, c.New.Status)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"old.yaml":            "attributeMapping:\n  google.subject: assertion.sub\n",
+		"new.json":            `{"attributeMapping": {"google.subject": "assertion.sub"}, "aws": {"accountId": "123456789012"}}`,
+		"tokens/a.json":       `{"sub": "a"}`,
+		"tokens/nested/b.jwt": "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJiIn0.c2ln",
+		"tokens/.hidden":      "ignored",
+		"empty.yaml":          "attributeCondition: 'true'\n",
+		"subject.yaml":        "attributeMapping:\n  attribute.sub: assertion.sub\n",
+		"empty.tf":            "resource \"google_iam_workload_identity_pool_provider\" \"github\" {\n  workload_identity_pool_provider_id = \"github\"\n  oidc { issuer_uri = \"https://token.actions.githubusercontent.com\" }\n}\n",
+		"main.tf":             "resource \"google_iam_workload_identity_pool_provider\" \"github\" {\n  workload_identity_pool_provider_id = \"github\"\n  attribute_mapping = { \"google.subject\" = \"assertion.sub\" }\n  oidc { issuer_uri = \"https://token.actions.githubusercontent.com\" }\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path      string
+		backend   provider.Backend
+		exception bool
+	}{
+		{path: "old.yaml", backend: provider.OIDC},
+		{path: "new.json", backend: provider.AWS},
+		{path: "main.tf", backend: provider.OIDC},
+		// invalid configurations
+		{path: "empty.yaml", exception: true},
+		{path: "subject.yaml", exception: true},
+		{path: "empty.tf", exception: true},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			path := filepath.Join(dir, tc.path)
+
+			c, err := LoadConfig(path, "github")
+
+			if tc.exception {
+				if err == nil {
+					t.Fatalf("LoadConfig(%s) -> expect exception", path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig(%s) = %s, expected no error", path, err)
+			}
+			if c.Backend != tc.backend {
+				t.Fatalf("LoadConfig(%s) = %s, expected %s", path, provider.BackendName(c.Backend), provider.BackendName(tc.backend))
+			}
+		})
+	}
+
+	tokens, err := LoadCorpus(filepath.Join(dir, "tokens"))
+
+	if err != nil {
+		t.Fatalf("LoadCorpus() = %s, expected no error", err)
+	}
+	if len(tokens) != 2 || tokens[0].Name != "a.json" || tokens[1].Payload != `{"sub":"b"}` {
+		t.Fatalf("LoadCorpus() = %+v, expected a.json and nested/b.jwt", tokens)
+	}
+}
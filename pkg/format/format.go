@@ -0,0 +1,133 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/common"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/parser"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// DefaultWidth is the column after which an expression is broken at '&&' and '||'.
+const DefaultWidth = 80
+
+// Options of the formatter
+type Options struct {
+	// Width is the column after which an expression is broken at '&&' and '||' (DefaultWidth when unset).
+	// A negative width breaks the expression at every '&&' and '||'.
+	Width int
+	// Minify prints the expression on a single line without optional spaces.
+	Minify bool
+}
+
+// Parse parses a CEL expression, keeping the macro calls so that the expression can be printed back
+func Parse(expr string) (*exprpb.ParsedExpr, error) {
+	p, err := parser.NewParser(parser.Macros(parser.AllMacros...), parser.PopulateMacroCalls(true))
+
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, errs := p.Parse(common.NewTextSource(expr))
+
+	if len(errs.GetErrors()) > 0 {
+		return nil, fmt.Errorf("error parsing CEL expression: %s", errs.ToDisplayString())
+	}
+	return parsed, nil
+}
+
+// Expression prints a CEL expression in its canonical form.
+// Strings are double quoted, redundant parentheses are removed and
+// long expressions are broken before '&&' and '||'.
+func Expression(expr string, opts *Options) (string, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	parsed, err := Parse(expr)
+
+	if err != nil {
+		return "", err
+	}
+
+	if opts.Minify {
+		out, err := parser.Unparse(parsed.GetExpr(), parsed.GetSourceInfo(), parser.WrapOnOperators())
+
+		if err != nil {
+			return "", err
+		}
+		return minify(out), nil
+	}
+
+	width := opts.Width
+	switch {
+	case width == 0:
+		width = DefaultWidth
+	case width < 0:
+		width = 1
+	}
+
+	out, err := parser.Unparse(parsed.GetExpr(), parsed.GetSourceInfo(),
+		parser.WrapOnColumn(width),
+		parser.WrapOnOperators(operators.LogicalAnd, operators.LogicalOr),
+		parser.WrapAfterColumnLimit(false),
+	)
+
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// Mapping prints every expression of an attribute mapping in its canonical form
+func Mapping(mapping map[string]string, opts *Options) (map[string]string, error) {
+	out := make(map[string]string, len(mapping))
+
+	for k, v := range mapping {
+		expr, err := Expression(v, opts)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		out[k] = expr
+	}
+	return out, nil
+}
+
+// minify removes the spaces surrounding symbols (eg. 'a == b' becomes 'a==b'),
+// spaces between words (eg. 'a in b') and within string literals are kept
+func minify(expr string) string {
+	var sb strings.Builder
+	inString := false
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+
+		switch {
+		case inString:
+			sb.WriteByte(c)
+			if c == '\\' && i+1 < len(expr) {
+				i++
+				sb.WriteByte(expr[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			sb.WriteByte(c)
+		case c == ' ' || c == '\n':
+			if i > 0 && i+1 < len(expr) && isWord(expr[i-1]) && isWord(expr[i+1]) {
+				sb.WriteByte(' ')
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func isWord(c byte) bool {
+	return c == '_' || c == '.' || c == '"' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,120 @@
+package format
+
+import (
+	"fmt"
+	"testing"
+
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"google.golang.org/protobuf/proto"
+)
+
+// clearIDs removes the expression ids, which depend on the layout of the source
+func clearIDs(e *exprpb.Expr) {
+	if e == nil {
+		return
+	}
+	e.Id = 0
+
+	switch k := e.ExprKind.(type) {
+	case *exprpb.Expr_SelectExpr:
+		clearIDs(k.SelectExpr.GetOperand())
+	case *exprpb.Expr_CallExpr:
+		clearIDs(k.CallExpr.GetTarget())
+		for _, arg := range k.CallExpr.GetArgs() {
+			clearIDs(arg)
+		}
+	case *exprpb.Expr_ListExpr:
+		for _, elem := range k.ListExpr.GetElements() {
+			clearIDs(elem)
+		}
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range k.StructExpr.GetEntries() {
+			entry.Id = 0
+			clearIDs(entry.GetMapKey())
+			clearIDs(entry.GetValue())
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		c := k.ComprehensionExpr
+		for _, sub := range []*exprpb.Expr{c.GetIterRange(), c.GetAccuInit(), c.GetLoopCondition(), c.GetLoopStep(), c.GetResult()} {
+			clearIDs(sub)
+		}
+	}
+}
+
+func ast(t *testing.T, expr string) *exprpb.Expr {
+	t.Helper()
+
+	parsed, err := Parse(expr)
+
+	if err != nil {
+		t.Fatalf("Parse(%s) = %s, expected no error", expr, err)
+	}
+
+	e := parsed.GetExpr()
+	clearIDs(e)
+	return e
+}
+
+func TestExpression(t *testing.T) {
+	tests := []struct {
+		expr     string
+		opts     *Options
+		expected string
+	}{
+		{
+			expr:     `assertion.repository_owner=='octo-org'`,
+			expected: `assertion.repository_owner == "octo-org"`,
+		},
+		{
+			expr:     `(('admins' in google.groups))`,
+			expected: `"admins" in google.groups`,
+		},
+		{
+			expr:     `assertion.sub.extract('repo:{repo}:') == 'octo-org/app' && assertion.ref == 'refs/heads/main' || assertion.actor == "admin"`,
+			opts:     &Options{Width: -1},
+			expected: "assertion.sub.extract(\"repo:{repo}:\") == \"octo-org/app\"\n&& assertion.ref == \"refs/heads/main\"\n|| assertion.actor == \"admin\"",
+		},
+		{
+			expr:     `assertion.repository_owner == 'octo-org' && assertion.ref == 'refs/heads/main'`,
+			expected: `assertion.repository_owner == "octo-org" && assertion.ref == "refs/heads/main"`,
+		},
+		{
+			expr:     "assertion.groups.exists(g, g == 'admins')  &&\n !(assertion.id in ['a b', \"c\\\"d\"]) && size(assertion.sub) > 1 - -1",
+			opts:     &Options{Minify: true},
+			expected: `assertion.groups.exists(g,g=="admins")&&!(assertion.id in["a b","c\"d"])&&size(assertion.sub)>1--1`,
+		},
+		{
+			expr:     `{'admins': 'admin', 'devs': 'dev'}[assertion.role]`,
+			expected: `{"admins": "admin", "devs": "dev"}[assertion.role]`,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			out, err := Expression(tc.expr, tc.opts)
+
+			if err != nil {
+				t.Fatalf("Expression(%s) = %s, expected no error", tc.expr, err)
+			}
+			if out != tc.expected {
+				t.Fatalf("Expression(%s) = %s, expected %s", tc.expr, out, tc.expected)
+			}
+
+			// formatting must never change the meaning of an expression
+			if !proto.Equal(ast(t, tc.expr), ast(t, out)) {
+				t.Fatalf("Expression(%s) = %s changes the AST", tc.expr, out)
+			}
+		})
+	}
+}
+
+func TestExpressionError(t *testing.T) {
+	if _, err := Expression(`assertion.sub ==`, nil); err == nil {
+		t.Fatalf("Expression() -> expect exception")
+	}
+
+	if _, err := Mapping(map[string]string{"google.subject": `assertion.sub +`}, nil); err == nil {
+		t.Fatalf("Mapping() -> expect exception")
+	}
+}
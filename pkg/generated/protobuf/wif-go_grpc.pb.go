@@ -0,0 +1,189 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: wif-go.proto
+
+package protobuf
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	WifService_Evaluate_FullMethodName      = "/wifgo.v1beta.WifService/Evaluate"
+	WifService_Validate_FullMethodName      = "/wifgo.v1beta.WifService/Validate"
+	WifService_BatchEvaluate_FullMethodName = "/wifgo.v1beta.WifService/BatchEvaluate"
+)
+
+// WifServiceClient is the client API for WifService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WifServiceClient interface {
+	// Evaluate derives the attributes of a token and checks them against the attribute condition.
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+	// Validate checks an attribute mapping and an attribute condition without a token.
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// BatchEvaluate evaluates several tokens against the same attribute mapping and attribute condition.
+	BatchEvaluate(ctx context.Context, in *BatchEvaluateRequest, opts ...grpc.CallOption) (*BatchEvaluateResponse, error)
+}
+
+type wifServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWifServiceClient(cc grpc.ClientConnInterface) WifServiceClient {
+	return &wifServiceClient{cc}
+}
+
+func (c *wifServiceClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, WifService_Evaluate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wifServiceClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, WifService_Validate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wifServiceClient) BatchEvaluate(ctx context.Context, in *BatchEvaluateRequest, opts ...grpc.CallOption) (*BatchEvaluateResponse, error) {
+	out := new(BatchEvaluateResponse)
+	err := c.cc.Invoke(ctx, WifService_BatchEvaluate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WifServiceServer is the server API for WifService service.
+// All implementations must embed UnimplementedWifServiceServer
+// for forward compatibility
+type WifServiceServer interface {
+	// Evaluate derives the attributes of a token and checks them against the attribute condition.
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+	// Validate checks an attribute mapping and an attribute condition without a token.
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// BatchEvaluate evaluates several tokens against the same attribute mapping and attribute condition.
+	BatchEvaluate(context.Context, *BatchEvaluateRequest) (*BatchEvaluateResponse, error)
+	mustEmbedUnimplementedWifServiceServer()
+}
+
+// UnimplementedWifServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedWifServiceServer struct {
+}
+
+func (UnimplementedWifServiceServer) Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (UnimplementedWifServiceServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedWifServiceServer) BatchEvaluate(context.Context, *BatchEvaluateRequest) (*BatchEvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchEvaluate not implemented")
+}
+func (UnimplementedWifServiceServer) mustEmbedUnimplementedWifServiceServer() {}
+
+// UnsafeWifServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WifServiceServer will
+// result in compilation errors.
+type UnsafeWifServiceServer interface {
+	mustEmbedUnimplementedWifServiceServer()
+}
+
+func RegisterWifServiceServer(s grpc.ServiceRegistrar, srv WifServiceServer) {
+	s.RegisterService(&WifService_ServiceDesc, srv)
+}
+
+func _WifService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WifServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WifService_Evaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WifServiceServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WifService_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WifServiceServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WifService_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WifServiceServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WifService_BatchEvaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchEvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WifServiceServer).BatchEvaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WifService_BatchEvaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WifServiceServer).BatchEvaluate(ctx, req.(*BatchEvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WifService_ServiceDesc is the grpc.ServiceDesc for WifService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WifService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wifgo.v1beta.WifService",
+	HandlerType: (*WifServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Evaluate",
+			Handler:    _WifService_Evaluate_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _WifService_Validate_Handler,
+		},
+		{
+			MethodName: "BatchEvaluate",
+			Handler:    _WifService_BatchEvaluate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "wif-go.proto",
+}
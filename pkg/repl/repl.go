@@ -0,0 +1,302 @@
+package repl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/attribute"
+	"github.com/loicsikidi/wif-go/pkg/complete"
+	"github.com/loicsikidi/wif-go/pkg/token"
+	"github.com/loicsikidi/wif-go/pkg/token/inspect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// CommandPrefix prefixes REPL commands (eg. :map)
+const CommandPrefix = ":"
+
+// ErrQuit is returned by Execute when the user leaves the REPL.
+var ErrQuit = errors.New("quit")
+
+// Help describes the REPL commands.
+const Help = `Type a CEL expression using 'assertion', 'google' and 'attribute' to evaluate it.
+
+Commands:
+  :load <file>          load a token (JWT or JSON claims) from a file
+  :token <token>        load a token (JWT or JSON claims)
+  :map                  print the attribute mapping
+  :map <attr> <expr>    map an attribute (eg. :map google.subject assertion.sub) and rerun the pipeline
+  :unmap <attr>         remove an attribute from the mapping and rerun the pipeline
+  :cond                 print the attribute condition
+  :cond <expr>          set the attribute condition and rerun the pipeline
+  :uncond               remove the attribute condition and rerun the pipeline
+  :run                  rerun the pipeline (attribute mapping then attribute condition)
+  :claims               print the claim paths of the token
+  :help                 print this help
+  :quit                 leave the REPL`
+
+var commands = []string{":load", ":token", ":map", ":unmap", ":cond", ":uncond", ":run", ":claims", ":help", ":quit"}
+
+// Session holds the state of a REPL
+type Session struct {
+	// Provider decoding the token (eg. oidc.Provider).
+	Provider provider.Provider
+	// AttributeMapping set with ':map'.
+	AttributeMapping map[string]string
+	// AttributeCondition set with ':cond'.
+	AttributeCondition string
+
+	payload    string
+	claims     map[string]any
+	input      map[string]any
+	attributes map[string]any
+	env        *cel.Env
+}
+
+// New returns a session using the CEL environment of an attribute condition
+func New(p provider.Provider) (*Session, error) {
+	env, err := compiler.NewEnv(&attribute.Provider{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		Provider:         p,
+		AttributeMapping: map[string]string{},
+		attributes:       map[string]any{},
+		env:              env,
+	}, nil
+}
+
+// Load loads a token, either a JWT or a JSON document holding the claims
+func (s *Session) Load(raw string) error {
+	payload, err := token.Payload(raw)
+
+	if err != nil {
+		return err
+	}
+
+	input, err := s.Provider.GetInputVar(payload)
+
+	if err != nil {
+		return err
+	}
+
+	claims := map[string]any{}
+	if err := json.Unmarshal([]byte(payload), &claims); err != nil {
+		return fmt.Errorf("the token claims must be a JSON object: %w", err)
+	}
+
+	s.payload = payload
+	s.input = input
+	s.claims = claims
+	s.attributes = map[string]any{}
+	return nil
+}
+
+// Eval evaluates an expression against the token and the attributes derived by the last run
+func (s *Session) Eval(expr string) (ref.Val, error) {
+	if s.input == nil {
+		return nil, fmt.Errorf("no token loaded, use ':load <file>' or ':token <token>'")
+	}
+
+	input, err := compiler.ConditionInput(s.input, s.attributes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return compiler.Eval(s.env, input, expr)
+}
+
+// Run runs the whole pipeline, ie. the attribute mapping then the attribute condition (cf. compiler.Program).
+// Derived attributes are kept even when the attribute condition rejects the token,
+// so they can be used in expressions, they are cleared when the attribute mapping fails.
+func (s *Session) Run() (map[string]any, error) {
+	if s.payload == "" {
+		return nil, fmt.Errorf("no token loaded, use ':load <file>' or ':token <token>'")
+	}
+	s.attributes = map[string]any{}
+
+	c := &compiler.Compiler{
+		Input: &compiler.Input{
+			AttributeMapping:   s.AttributeMapping,
+			AttributeCondition: s.AttributeCondition,
+		},
+		Provider: s.Provider,
+	}
+
+	p, err := c.Compile()
+
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := p.Derive(s.payload)
+
+	if attributes != nil {
+		s.attributes = attributes
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// Execute runs a line typed by the user, either a command or an expression, and returns the output
+func (s *Session) Execute(line string) (string, error) {
+	line = strings.TrimSpace(line)
+
+	if line == "" {
+		return "", nil
+	}
+
+	if !strings.HasPrefix(line, CommandPrefix) {
+		val, err := s.Eval(line)
+
+		if err != nil {
+			return "", err
+		}
+		return Format(val), nil
+	}
+
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case ":help":
+		return Help, nil
+	case ":quit", ":exit":
+		return "", ErrQuit
+	case ":load":
+		data, err := os.ReadFile(arg)
+
+		if err != nil {
+			return "", err
+		}
+		if err := s.Load(string(data)); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("token loaded (%d claims)", len(s.claims)), nil
+	case ":token":
+		if err := s.Load(arg); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("token loaded (%d claims)", len(s.claims)), nil
+	case ":claims":
+		return strings.Join(inspect.Paths(attribute.Assertion, s.claims), "\n"), nil
+	case ":map":
+		if arg == "" {
+			return s.formatMapping(), nil
+		}
+
+		key, expr, ok := strings.Cut(arg, " ")
+		if !ok {
+			return "", fmt.Errorf("usage: :map <attr> <expr>")
+		}
+		s.AttributeMapping[key] = strings.TrimSpace(expr)
+		return s.rerun()
+	case ":unmap":
+		delete(s.AttributeMapping, arg)
+		return s.rerun()
+	case ":cond":
+		if arg == "" {
+			return s.AttributeCondition, nil
+		}
+		s.AttributeCondition = arg
+		return s.rerun()
+	case ":uncond":
+		s.AttributeCondition = ""
+		return s.rerun()
+	case ":run":
+		return s.rerun()
+	default:
+		return "", fmt.Errorf("unknown command %q, type ':help' to list commands", name)
+	}
+}
+
+// rerun runs the pipeline and describes its outcome
+func (s *Session) rerun() (string, error) {
+	attributes, err := s.Run()
+
+	switch {
+	case errors.Is(err, compiler.ErrAttrConditionFailed):
+		return fmt.Sprintf("REJECTED: %s\n%s", err, formatJSON(s.attributes)), nil
+	case err != nil:
+		return "", err
+	default:
+		return fmt.Sprintf("ACCEPTED\n%s", formatJSON(attributes)), nil
+	}
+}
+
+func (s *Session) formatMapping() string {
+	keys := make([]string, 0, len(s.AttributeMapping))
+	for k := range s.AttributeMapping {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s = %s", k, s.AttributeMapping[k]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatJSON(v any) string {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
+
+// Format renders a CEL value as JSON followed by its type (eg. "octo-org" (string))
+func Format(val ref.Val) string {
+	typeName := val.Type().TypeName()
+
+	native, err := val.ConvertToNative(reflect.TypeOf(&structpb.Value{}))
+	if err != nil {
+		return fmt.Sprintf("%v (%s)", val.Value(), typeName)
+	}
+
+	out, err := protojson.Marshal(native.(*structpb.Value))
+	if err != nil {
+		return fmt.Sprintf("%v (%s)", val.Value(), typeName)
+	}
+	return fmt.Sprintf("%s (%s)", out, typeName)
+}
+
+// Complete returns the candidates completing a line
+func (s *Session) Complete(line string) []string {
+	if strings.HasPrefix(line, CommandPrefix) && !strings.Contains(line, " ") {
+		var out []string
+		for _, c := range commands {
+			if strings.HasPrefix(c, line) {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+
+	// complete the last word of the line
+	completer := &complete.Completer{Claims: s.claims, AttributeMapping: s.AttributeMapping, Condition: true}
+	runes := []rune(line)
+	res := completer.Complete(line, len(runes))
+
+	var out []string
+	for _, sg := range res.Suggestions {
+		out = append(out, string(runes[:res.From])+sg.Label)
+	}
+	return out
+}
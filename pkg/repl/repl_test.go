@@ -0,0 +1,188 @@
+package repl
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/oidc"
+)
+
+const claims = `{"sub": "repo:octo-org/octo-repo:ref:refs/heads/main", "repository_owner": "octo-org", "groups": ["admins"], "https://example.com/role": "dev"}`
+
+func newSession(t *testing.T) *Session {
+	t.Helper()
+
+	s, err := New(&oidc.Provider{})
+
+	if err != nil {
+		t.Fatalf("New() = %s, expected no error", err)
+	}
+
+	if err := s.Load(claims); err != nil {
+		t.Fatalf("Load() = %s, expected no error", err)
+	}
+	return s
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		lines    []string
+		expected string
+		isError  bool
+	}{
+		{
+			lines:    []string{`assertion.repository_owner`},
+			expected: `"octo-org" (string)`,
+		},
+		{
+			lines:    []string{`assertion.sub.extract('repo:{repo}:')`},
+			expected: `"octo-org/octo-repo" (string)`,
+		},
+		{
+			lines:    []string{`size(assertion.groups)`},
+			expected: `1 (int)`,
+		},
+		{
+			lines:    []string{`assertion['https://example.com/role'] == 'dev'`},
+			expected: `true (bool)`,
+		},
+		{
+			lines:    []string{`:map google.subject assertion.sub`, `:map attribute.owner assertion.repository_owner`, `attribute.owner`},
+			expected: `"octo-org" (string)`,
+		},
+		{
+			lines:    []string{`:map google.subject assertion.sub`, `:cond assertion.repository_owner == 'evil'`},
+			expected: "REJECTED",
+		},
+		{
+			lines:    []string{`:map google.subject assertion.sub`, `:cond assertion.repository_owner == 'octo-org'`},
+			expected: "ACCEPTED",
+		},
+		{
+			lines:    []string{`:map google.subject assertion.sub`, `:map attribute.owner assertion.repository_owner`, `:unmap attribute.owner`, `:map`},
+			expected: "google.subject = assertion.sub",
+		},
+		{
+			lines:   []string{`assertion.missing`},
+			isError: true,
+		},
+		// attributes of a previous run don't survive a failed run
+		{
+			lines:   []string{`:map google.subject assertion.sub`, `:map attribute.owner assertion.repository_owner`, `:map attribute.owner assertion.missing`, `attribute.owner`},
+			isError: true,
+		},
+		// attributes are kept when the attribute condition rejects the token
+		{
+			lines:    []string{`:map google.subject assertion.sub`, `:map attribute.owner assertion.repository_owner`, `:cond attribute.owner == 'evil'`, `attribute.owner`},
+			expected: `"octo-org" (string)`,
+		},
+		// the attribute condition is checked by the compiler
+		{
+			lines:   []string{`:map google.subject assertion.sub`, fmt.Sprintf(`:cond '%s' == ''`, strings.Repeat("a", compiler.MaximumAttributeConditionLengthInBytes))},
+			isError: true,
+		},
+		{
+			lines:   []string{`:unknown`},
+			isError: true,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			s := newSession(t)
+
+			var (
+				out string
+				err error
+			)
+			for _, line := range tc.lines {
+				out, err = s.Execute(line)
+			}
+
+			if tc.isError {
+				if err == nil {
+					t.Fatalf("Execute(%v) -> expect exception", tc.lines)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Execute(%v) = %s, expected no error", tc.lines, err)
+			}
+			if !strings.HasPrefix(out, tc.expected) {
+				t.Fatalf("Execute(%v) = %s, expected %s", tc.lines, out, tc.expected)
+			}
+		})
+	}
+}
+
+func TestExecuteWithoutToken(t *testing.T) {
+	s, _ := New(&oidc.Provider{})
+
+	if _, err := s.Execute("assertion.sub"); err == nil {
+		t.Fatalf("Execute() -> expect exception")
+	}
+
+	if _, err := s.Execute(":quit"); !errors.Is(err, ErrQuit) {
+		t.Fatalf("Execute(:quit) = %v, expected %s", err, ErrQuit)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	s := newSession(t)
+	s.AttributeMapping["attribute.owner"] = "assertion.repository_owner"
+
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{
+			line:     ":ma",
+			expected: []string{":map"},
+		},
+		{
+			line:     "assertion.repo",
+			expected: []string{"assertion.repository_owner"},
+		},
+		{
+			line:     "'admins' in assertion.gr",
+			expected: []string{"'admins' in assertion.groups"},
+		},
+		{
+			line:     "assertion.h",
+			expected: []string{"assertion['https://example.com/role']"},
+		},
+		{
+			line:     "attribute.",
+			expected: []string{"attribute.owner"},
+		},
+		{
+			line:     "google.s",
+			expected: []string{"google.subject"},
+		},
+		{
+			line:     "assertion.sub.ext",
+			expected: []string{"assertion.sub.extract("},
+		},
+		{
+			line:     "ass",
+			expected: []string{"assertion"},
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			got := s.Complete(tc.line)
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Complete(%s) = %v, expected %v", tc.line, got, tc.expected)
+			}
+		})
+	}
+}
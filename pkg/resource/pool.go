@@ -0,0 +1,128 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limitations set by Google Cloud Platform.
+//
+// (See more at https://cloud.google.com/iam/docs/reference/rest/v1/projects.locations.workloadIdentityPools)
+const (
+	// Pool and provider IDs can't be shorter than 4 characters
+	MinimumIDLength = 4
+	// Pool and provider IDs can't exceed 32 characters
+	MaximumIDLength = 32
+	// Display names can't exceed 32 characters
+	MaximumDisplayNameLength = 32
+	// Descriptions can't exceed 256 characters
+	MaximumDescriptionLength = 256
+	// Pool and provider IDs can't start with this prefix
+	ReservedIDPrefix = "gcp-"
+)
+
+var (
+	idRegexp            = regexp.MustCompile("^[a-z0-9-]+$")
+	projectNumberRegexp = regexp.MustCompile("^[0-9]+$")
+)
+
+// Pool represents a Workload Identity Pool and the providers attached to it
+type Pool struct {
+	// [Required] ProjectNumber is the number of the Google Cloud project owning the pool (eg. 123456789012).
+	ProjectNumber string
+	// [Required] ID is the identifier of the pool (eg. my-pool).
+	ID string
+	// [Optional] DisplayName is a display name for the pool.
+	DisplayName string
+	// [Optional] Description is a description for the pool.
+	Description string
+	// [Optional] Disabled means that the pool can't be used to exchange tokens.
+	Disabled bool
+	// [Optional] Providers attached to the pool.
+	Providers []*Provider
+}
+
+// validateID checks that an ID complies with the format expected by Google Cloud Platform
+func validateID(kind, id string) error {
+	if len(id) < MinimumIDLength || len(id) > MaximumIDLength {
+		return fmt.Errorf("invalid %s ID %q: the length must be between %d and %d characters", kind, id, MinimumIDLength, MaximumIDLength)
+	}
+	if !idRegexp.MatchString(id) {
+		return fmt.Errorf("invalid %s ID %q: only lowercase letters, digits and hyphens are accepted", kind, id)
+	}
+	if strings.HasPrefix(id, ReservedIDPrefix) {
+		return fmt.Errorf("invalid %s ID %q: the prefix '%s' is reserved by Google Cloud Platform", kind, id, ReservedIDPrefix)
+	}
+	return nil
+}
+
+// validateDisplay checks display name and description lengths, counted in characters rather than bytes
+func validateDisplay(kind, displayName, description string) error {
+	if utf8.RuneCountInString(displayName) > MaximumDisplayNameLength {
+		return fmt.Errorf("the display name of a %s can't exceed %d characters", kind, MaximumDisplayNameLength)
+	}
+	if utf8.RuneCountInString(description) > MaximumDescriptionLength {
+		return fmt.Errorf("the description of a %s can't exceed %d characters", kind, MaximumDescriptionLength)
+	}
+	return nil
+}
+
+// Name returns the resource name of the pool
+// (eg. projects/123456789012/locations/global/workloadIdentityPools/my-pool)
+func (p *Pool) Name() string {
+	return fmt.Sprintf("projects/%s/locations/global/workloadIdentityPools/%s", p.ProjectNumber, p.ID)
+}
+
+// ProviderName returns the resource name of a provider attached to the pool
+func (p *Pool) ProviderName(providerID string) string {
+	return fmt.Sprintf("%s/providers/%s", p.Name(), providerID)
+}
+
+// Audience returns the default audience accepted by a provider attached to the pool.
+// It's also the audience expected by the Security Token Service during a token exchange.
+func (p *Pool) Audience(providerID string) string {
+	return fmt.Sprintf("//iam.googleapis.com/%s", p.ProviderName(providerID))
+}
+
+// Provider returns the provider matching the given ID or nil
+func (p *Pool) Provider(providerID string) *Provider {
+	for _, provider := range p.Providers {
+		if provider.ID == providerID {
+			return provider
+		}
+	}
+	return nil
+}
+
+// Validate checks the pool and its providers conformity
+func (p *Pool) Validate() error {
+	if !projectNumberRegexp.MatchString(p.ProjectNumber) {
+		return fmt.Errorf("invalid project number %q: only digits are accepted", p.ProjectNumber)
+	}
+
+	if err := validateID("pool", p.ID); err != nil {
+		return err
+	}
+
+	if err := validateDisplay("pool", p.DisplayName, p.Description); err != nil {
+		return err
+	}
+
+	ids := map[string]bool{}
+	for _, provider := range p.Providers {
+		if provider == nil {
+			return fmt.Errorf("pool %q contains an undefined provider", p.ID)
+		}
+		if ids[provider.ID] {
+			return fmt.Errorf("duplicate provider ID %q in pool %q", provider.ID, p.ID)
+		}
+		ids[provider.ID] = true
+
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
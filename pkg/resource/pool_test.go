@@ -0,0 +1,106 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+func newOidcProvider(id string) *Provider {
+	return &Provider{
+		ID:               id,
+		Type:             provider.OIDC,
+		AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"},
+		OIDC:             &OIDC{IssuerURI: "https://token.actions.githubusercontent.com"},
+	}
+}
+
+func TestPoolNames(t *testing.T) {
+	p := &Pool{ProjectNumber: "123456789012", ID: "my-pool"}
+
+	if got, expected := p.Name(), "projects/123456789012/locations/global/workloadIdentityPools/my-pool"; got != expected {
+		t.Fatalf("Name() = %s, expected %s", got, expected)
+	}
+	if got, expected := p.Audience("github"), "//iam.googleapis.com/projects/123456789012/locations/global/workloadIdentityPools/my-pool/providers/github"; got != expected {
+		t.Fatalf("Audience() = %s, expected %s", got, expected)
+	}
+}
+
+func TestPoolValidate(t *testing.T) {
+	tests := []struct {
+		pool    *Pool
+		isError bool
+	}{
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "my-pool", Providers: []*Provider{newOidcProvider("github"), newOidcProvider("gitlab")}},
+			isError: false,
+		},
+		// Test failure when the project number is not numeric
+		{
+			pool:    &Pool{ProjectNumber: "my-project", ID: "my-pool"},
+			isError: true,
+		},
+		// Test failure when the pool ID is too short
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "abc"},
+			isError: true,
+		},
+		// Test failure when the pool ID is too long
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "abcdefghijklmnopqrstuvwxyz0123456"},
+			isError: true,
+		},
+		// Test failure when the pool ID contains uppercase letters
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "My-Pool"},
+			isError: true,
+		},
+		// Test failure when the pool ID uses the reserved prefix
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "gcp-pool"},
+			isError: true,
+		},
+		// Test failure when the display name is too long
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "my-pool", DisplayName: "abcdefghijklmnopqrstuvwxyz0123456"},
+			isError: true,
+		},
+		// Test success when the display name has multi-byte characters within the limit
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "my-pool", DisplayName: strings.Repeat("é", MaximumDisplayNameLength)},
+			isError: false,
+		},
+		// Test failure when the display name has too many multi-byte characters
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "my-pool", DisplayName: strings.Repeat("é", MaximumDisplayNameLength+1)},
+			isError: true,
+		},
+		// Test failure when provider IDs are duplicated
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "my-pool", Providers: []*Provider{newOidcProvider("github"), newOidcProvider("github")}},
+			isError: true,
+		},
+		// Test failure when a provider is invalid
+		{
+			pool:    &Pool{ProjectNumber: "123456789012", ID: "my-pool", Providers: []*Provider{newOidcProvider("gh")}},
+			isError: true,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			err := tc.pool.Validate()
+
+			if tc.isError && err == nil {
+				t.Fatalf("Validate(%v) -> expect exception", tc.pool)
+			}
+			if !tc.isError && err != nil {
+				t.Fatalf("Validate(%v) = %s, expected no error", tc.pool, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package resource
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+// Limitations set by Google Cloud Platform.
+const (
+	// An OIDC provider can't accept more than 10 audiences
+	MaximumAllowedAudiences = 10
+)
+
+var awsAccountIDRegexp = regexp.MustCompile("^[0-9]{12}$")
+
+// OIDC holds the configuration specific to an OIDC provider
+type OIDC struct {
+	// [Required] IssuerURI is the OIDC issuer URL (eg. https://token.actions.githubusercontent.com).
+	IssuerURI string
+	// [Optional] AllowedAudiences are the values accepted in the 'aud' claim.
+	// When empty, only the default audience of the provider is accepted.
+	AllowedAudiences []string
+	// [Optional] JWKSJSON is the JSON Web Key Set used to verify tokens instead of the issuer's one.
+	JWKSJSON string
+}
+
+// AWS holds the configuration specific to an AWS provider
+type AWS struct {
+	// [Required] AccountID is the AWS account ID (eg. 123456789012).
+	AccountID string
+}
+
+// SAML holds the configuration specific to a SAML provider
+type SAML struct {
+	// [Required] IdPMetadataXML is the SAML identity provider metadata.
+	IdPMetadataXML string
+}
+
+// Provider represents a Workload Identity Pool Provider
+type Provider struct {
+	// [Required] ID is the identifier of the provider (eg. github).
+	ID string
+	// [Optional] DisplayName is a display name for the provider.
+	DisplayName string
+	// [Optional] Description is a description for the provider.
+	Description string
+	// [Optional] Disabled means that the provider can't be used to exchange tokens.
+	Disabled bool
+	// [Required] Type is the provider backend (eg. provider.OIDC).
+	Type provider.Backend
+	// [Required] AttributeMapping defines how to derive attributes from an external token (cf. compiler.Input).
+	AttributeMapping map[string]string
+	// [Optional] AttributeCondition is a CEL expression accepting or rejecting a credential (cf. compiler.Input).
+	AttributeCondition string
+	// OIDC is required when Type is provider.OIDC.
+	OIDC *OIDC
+	// AWS is required when Type is provider.AWS.
+	AWS *AWS
+	// SAML is required when Type is provider.SAML.
+	SAML *SAML
+}
+
+// Validate checks the provider conformity
+func (p *Provider) Validate() error {
+	if err := validateID("provider", p.ID); err != nil {
+		return err
+	}
+
+	if err := validateDisplay("provider", p.DisplayName, p.Description); err != nil {
+		return err
+	}
+
+	switch p.Type {
+	case provider.OIDC:
+		if p.OIDC == nil || p.OIDC.IssuerURI == "" {
+			return fmt.Errorf("provider %q: an issuer URI is required", p.ID)
+		}
+		if !strings.HasPrefix(p.OIDC.IssuerURI, "https://") {
+			return fmt.Errorf("provider %q: the issuer URI must use the 'https' scheme", p.ID)
+		}
+		if len(p.OIDC.AllowedAudiences) > MaximumAllowedAudiences {
+			return fmt.Errorf("provider %q: allowed audiences are limited to %d", p.ID, MaximumAllowedAudiences)
+		}
+	case provider.AWS:
+		if p.AWS == nil || !awsAccountIDRegexp.MatchString(p.AWS.AccountID) {
+			return fmt.Errorf("provider %q: a 12-digit AWS account ID is required", p.ID)
+		}
+	case provider.SAML:
+		if p.SAML == nil || p.SAML.IdPMetadataXML == "" {
+			return fmt.Errorf("provider %q: the identity provider metadata is required", p.ID)
+		}
+	default:
+		return fmt.Errorf("provider %q: unknown provider backend %d", p.ID, p.Type)
+	}
+
+	// AWS providers fall back to a default mapping when none is set
+	if p.Type != provider.AWS {
+		if _, ok := p.AttributeMapping[compiler.GoogleSubject]; !ok {
+			return fmt.Errorf("provider %q: the attribute mapping must define '%s'", p.ID, compiler.GoogleSubject)
+		}
+	}
+	return nil
+}
+
+// Input returns the compiler input evaluating the given payload against the provider
+func (p *Provider) Input(payload string) *compiler.Input {
+	return &compiler.Input{
+		Payload:            payload,
+		AttributeMapping:   p.AttributeMapping,
+		AttributeCondition: p.AttributeCondition,
+	}
+}
+
+// Compiler returns a compiler evaluating the given payload against the provider
+func (p *Provider) Compiler(payload string) (*compiler.Compiler, error) {
+	backend, err := provider.New(p.Type)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiler.Compiler{
+		Input:    p.Input(payload),
+		Provider: backend,
+	}, nil
+}
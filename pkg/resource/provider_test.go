@@ -0,0 +1,101 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+func TestProviderValidate(t *testing.T) {
+	tests := []struct {
+		provider *Provider
+		isError  bool
+	}{
+		{
+			provider: newOidcProvider("github"),
+			isError:  false,
+		},
+		{
+			provider: &Provider{ID: "aws-prod", Type: provider.AWS, AWS: &AWS{AccountID: "123456789012"}},
+			isError:  false,
+		},
+		// Test success when the description has multi-byte characters within the limit
+		{
+			provider: &Provider{ID: "github", Type: provider.OIDC, AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"}, OIDC: &OIDC{IssuerURI: "https://example.com"}, Description: strings.Repeat("日", MaximumDescriptionLength)},
+			isError:  false,
+		},
+		// Test failure when the description has too many multi-byte characters
+		{
+			provider: &Provider{ID: "github", Type: provider.OIDC, AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"}, OIDC: &OIDC{IssuerURI: "https://example.com"}, Description: strings.Repeat("日", MaximumDescriptionLength+1)},
+			isError:  true,
+		},
+		// Test failure when the issuer is missing
+		{
+			provider: &Provider{ID: "github", Type: provider.OIDC, AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"}},
+			isError:  true,
+		},
+		// Test failure when the issuer doesn't use https
+		{
+			provider: &Provider{ID: "github", Type: provider.OIDC, AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"}, OIDC: &OIDC{IssuerURI: "http://example.com"}},
+			isError:  true,
+		},
+		// Test failure when there is too much allowed audiences
+		{
+			provider: &Provider{ID: "github", Type: provider.OIDC, AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"}, OIDC: &OIDC{IssuerURI: "https://example.com", AllowedAudiences: make([]string, MaximumAllowedAudiences+1)}},
+			isError:  true,
+		},
+		// Test failure when google.subject is not mapped
+		{
+			provider: &Provider{ID: "github", Type: provider.OIDC, AttributeMapping: map[string]string{}, OIDC: &OIDC{IssuerURI: "https://example.com"}},
+			isError:  true,
+		},
+		// Test failure when the AWS account ID is invalid
+		{
+			provider: &Provider{ID: "aws-prod", Type: provider.AWS, AWS: &AWS{AccountID: "1234"}},
+			isError:  true,
+		},
+		// Test failure when the SAML metadata is missing
+		{
+			provider: &Provider{ID: "okta", Type: provider.SAML, AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.subject"}},
+			isError:  true,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			err := tc.provider.Validate()
+
+			if tc.isError && err == nil {
+				t.Fatalf("Validate(%v) -> expect exception", tc.provider)
+			}
+			if !tc.isError && err != nil {
+				t.Fatalf("Validate(%v) = %s, expected no error", tc.provider, err)
+			}
+		})
+	}
+}
+
+func TestProviderCompiler(t *testing.T) {
+	c, err := newOidcProvider("github").Compiler(`{"sub": "repo:octo-org/octo-repo:ref:refs/heads/main"}`)
+
+	if err != nil {
+		t.Fatalf("Compiler() = %s, expected no error", err)
+	}
+
+	attrs, err := c.Run()
+
+	if err != nil {
+		t.Fatalf("Run() = %s, expected no error", err)
+	}
+	if attrs[compiler.GoogleSubject] != "repo:octo-org/octo-repo:ref:refs/heads/main" {
+		t.Fatalf("Run() = %v, unexpected '%s'", attrs, compiler.GoogleSubject)
+	}
+
+	if _, err := (&Provider{ID: "aws-prod", Type: provider.AWS}).Compiler("{}"); err == nil {
+		t.Fatalf("Compiler() -> expect exception for an unsupported provider")
+	}
+}
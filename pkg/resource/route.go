@@ -0,0 +1,258 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/token"
+	"github.com/loicsikidi/wif-go/pkg/token/jwks"
+)
+
+var (
+	// ErrPoolDisabled means that the pool can't be used to exchange tokens.
+	ErrPoolDisabled = errors.New("the workload identity pool is disabled")
+	// ErrProviderDisabled means that the provider can't be used to exchange tokens.
+	ErrProviderDisabled = errors.New("the provider is disabled")
+	// ErrAudienceMismatch means that the audience sent to the Security Token Service doesn't target the provider.
+	ErrAudienceMismatch = errors.New("the requested audience doesn't target the provider")
+	// ErrIssuerMismatch means that the 'iss' claim of the token doesn't match the provider issuer.
+	ErrIssuerMismatch = errors.New("the token issuer doesn't match the provider issuer")
+	// ErrTokenAudienceMismatch means that the 'aud' claim of the token isn't accepted by the provider.
+	ErrTokenAudienceMismatch = errors.New("the token audience isn't accepted by the provider")
+	// ErrNoMatchingProvider means that none of the pool providers accepts the token.
+	ErrNoMatchingProvider = errors.New("no provider of the pool accepts the given token")
+)
+
+// Rejection explains why a provider didn't match a token
+type Rejection struct {
+	// ProviderID is the ID of the rejected provider.
+	ProviderID string
+	// Err is the reason of the rejection.
+	Err error
+}
+
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("provider %q: %s", r.ProviderID, r.Err)
+}
+
+func (r *Rejection) Unwrap() error {
+	return r.Err
+}
+
+// RouteResult is the outcome of routing a token through a pool
+type RouteResult struct {
+	// Provider is the provider selected for the token (nil if none matches).
+	Provider *Provider
+	// Candidate is the only provider matching the issuer and the audience of a token it doesn't accept
+	// (eg. rejected by its attribute condition), Route returns its error. It's nil otherwise.
+	Candidate *Provider
+	// Attributes are the attributes derived by the selected provider.
+	Attributes map[string]any
+	// Rejections explains why the other providers didn't match.
+	Rejections []*Rejection
+}
+
+// claims holds the OIDC claims used to route a token
+type claims struct {
+	Issuer   string
+	Audience []string
+}
+
+// parseClaims extracts 'iss' and 'aud' claims from a JSON payload
+func parseClaims(payload string) (*claims, error) {
+	var raw struct {
+		Issuer   string          `json:"iss"`
+		Audience json.RawMessage `json:"aud"`
+	}
+
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		return nil, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+
+	c := &claims{Issuer: raw.Issuer}
+
+	if len(raw.Audience) > 0 {
+		// 'aud' is either a string or a list of strings (cf. RFC 7519)
+		var aud string
+		if err := json.Unmarshal(raw.Audience, &aud); err == nil {
+			c.Audience = []string{aud}
+		} else if err := json.Unmarshal(raw.Audience, &c.Audience); err != nil {
+			return nil, fmt.Errorf("the 'aud' claim must be a string or a list of strings")
+		}
+	}
+	return c, nil
+}
+
+// acceptedAudiences returns the token audiences accepted by a provider.
+//
+// When allowed audiences are not set, Google Cloud Platform accepts the provider
+// resource name with or without the https prefix.
+func (p *Pool) acceptedAudiences(pr *Provider) []string {
+	if pr.OIDC != nil && len(pr.OIDC.AllowedAudiences) > 0 {
+		return pr.OIDC.AllowedAudiences
+	}
+	audience := p.Audience(pr.ID)
+	return []string{audience, "https:" + audience}
+}
+
+// match checks if a provider is a candidate for a token
+func (p *Pool) match(pr *Provider, c *claims, audience string) error {
+	if audience != "" && strings.TrimPrefix(audience, "https:") != p.Audience(pr.ID) {
+		return fmt.Errorf("%w: got '%s', expected '%s'", ErrAudienceMismatch, audience, p.Audience(pr.ID))
+	}
+
+	if pr.Disabled {
+		return ErrProviderDisabled
+	}
+
+	if pr.Type != provider.OIDC {
+		return fmt.Errorf("provider '%s' is not supported yet", provider.BackendName(pr.Type))
+	}
+
+	if pr.OIDC == nil {
+		return fmt.Errorf("the OIDC configuration is missing")
+	}
+
+	if err := checkIssuer(c, pr.OIDC.IssuerURI); err != nil {
+		return err
+	}
+	return checkAudience(c, p.acceptedAudiences(pr))
+}
+
+// checkIssuer checks the issuer of a token
+func checkIssuer(c *claims, issuer string) error {
+	if c.Issuer != issuer {
+		return fmt.Errorf("%w: got '%s', expected '%s'", ErrIssuerMismatch, c.Issuer, issuer)
+	}
+	return nil
+}
+
+// checkAudience checks that a token holds one of the accepted audiences
+func checkAudience(c *claims, accepted []string) error {
+	for _, a := range accepted {
+		for _, aud := range c.Audience {
+			if aud == a {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: got %q, expected one of %q", ErrTokenAudienceMismatch, c.Audience, accepted)
+}
+
+// Verify checks a token (a JWT or a JSON payload, cf. token.Payload) against the OIDC configuration of the provider,
+// regardless of a pool, and returns its JSON payload:
+//   - the signature is verified when the provider has a JWKS (only a JWT can be verified)
+//   - the 'iss' claim must match the issuer when it's set
+//   - the 'aud' claim must hold one of the allowed audiences when they're set (the default audience depends on the pool)
+func (p *Provider) Verify(raw string) (string, error) {
+	payload, err := token.Payload(raw)
+
+	if err != nil {
+		return "", err
+	}
+
+	if p.Type != provider.OIDC || p.OIDC == nil {
+		return payload, nil
+	}
+
+	if p.OIDC.JWKSJSON != "" {
+		if err := jwks.Verify(raw, p.OIDC.JWKSJSON); err != nil {
+			return "", err
+		}
+	}
+
+	c, err := parseClaims(payload)
+
+	if err != nil {
+		return "", err
+	}
+
+	if p.OIDC.IssuerURI != "" {
+		if err := checkIssuer(c, p.OIDC.IssuerURI); err != nil {
+			return "", err
+		}
+	}
+
+	if len(p.OIDC.AllowedAudiences) > 0 {
+		if err := checkAudience(c, p.OIDC.AllowedAudiences); err != nil {
+			return "", err
+		}
+	}
+	return payload, nil
+}
+
+// evaluate runs the attribute mapping and condition of a provider
+func evaluate(pr *Provider, payload string) (map[string]any, error) {
+	c, err := pr.Compiler(payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Run()
+}
+
+// Route selects the provider of the pool matching a token (a JSON payload) and
+// evaluates its attribute mapping and condition.
+//
+// Providers are selected by issuer and token audience. When audience is set (ie. the
+// audience sent to the Security Token Service), only the provider it targets is a candidate.
+//
+// When several providers are candidates, the first one accepting the token wins.
+// The result lists why each other provider didn't match.
+func (p *Pool) Route(payload string, audience string) (*RouteResult, error) {
+	if p.Disabled {
+		return nil, ErrPoolDisabled
+	}
+
+	c, err := parseClaims(payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RouteResult{}
+	var candidates []*Provider
+
+	for _, pr := range p.Providers {
+		if err := p.match(pr, c, audience); err != nil {
+			result.Rejections = append(result.Rejections, &Rejection{ProviderID: pr.ID, Err: err})
+			continue
+		}
+		candidates = append(candidates, pr)
+	}
+
+	var lastErr error
+	for _, pr := range candidates {
+		if result.Provider != nil {
+			err := fmt.Errorf("the token is already accepted by provider %q", result.Provider.ID)
+			result.Rejections = append(result.Rejections, &Rejection{ProviderID: pr.ID, Err: err})
+			continue
+		}
+
+		attrs, err := evaluate(pr, payload)
+
+		if err != nil {
+			lastErr = err
+			result.Rejections = append(result.Rejections, &Rejection{ProviderID: pr.ID, Err: err})
+			continue
+		}
+
+		result.Provider = pr
+		result.Attributes = attrs
+	}
+
+	if result.Provider != nil {
+		return result, nil
+	}
+
+	// a single candidate gives a more accurate error (eg. compiler.ErrAttrConditionFailed)
+	if len(candidates) == 1 {
+		result.Candidate = candidates[0]
+		return result, lastErr
+	}
+	return result, ErrNoMatchingProvider
+}
@@ -0,0 +1,207 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+const (
+	githubIssuer = "https://token.actions.githubusercontent.com"
+	gitlabIssuer = "https://gitlab.com"
+)
+
+func newPool() *Pool {
+	return &Pool{
+		ProjectNumber: "123456789012",
+		ID:            "ci-pool",
+		Providers: []*Provider{
+			{
+				ID:                 "github",
+				Type:               provider.OIDC,
+				AttributeMapping:   map[string]string{compiler.GoogleSubject: "assertion.sub"},
+				AttributeCondition: `assertion.repository_owner == "octo-org"`,
+				OIDC:               &OIDC{IssuerURI: githubIssuer, AllowedAudiences: []string{"wif"}},
+			},
+			{
+				ID:               "gitlab",
+				Type:             provider.OIDC,
+				AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"},
+				OIDC:             &OIDC{IssuerURI: gitlabIssuer},
+			},
+			{
+				ID:               "gitlab-old",
+				Disabled:         true,
+				Type:             provider.OIDC,
+				AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"},
+				OIDC:             &OIDC{IssuerURI: gitlabIssuer},
+			},
+		},
+	}
+}
+
+func TestRoute(t *testing.T) {
+	pool := newPool()
+	gitlabAudience := pool.Audience("gitlab")
+
+	type Expected struct {
+		ProviderID  string
+		CandidateID string
+		Rejections  int
+		ErrorType   error
+	}
+	tests := []struct {
+		payload  string
+		audience string
+		disabled bool
+		expected *Expected
+	}{
+		{
+			payload:  fmt.Sprintf(`{"iss": "%s", "aud": "wif", "sub": "repo:octo-org/app", "repository_owner": "octo-org"}`, githubIssuer),
+			expected: &Expected{ProviderID: "github", Rejections: 2},
+		},
+		{
+			payload:  fmt.Sprintf(`{"iss": "%s", "aud": ["%s"], "sub": "project_path:group/app"}`, gitlabIssuer, "https:"+gitlabAudience),
+			audience: gitlabAudience,
+			expected: &Expected{ProviderID: "gitlab", Rejections: 2},
+		},
+		// Test failure when the condition rejects the token
+		{
+			payload:  fmt.Sprintf(`{"iss": "%s", "aud": "wif", "sub": "repo:evil-org/app", "repository_owner": "evil-org"}`, githubIssuer),
+			expected: &Expected{CandidateID: "github", Rejections: 3, ErrorType: compiler.ErrAttrConditionFailed},
+		},
+		// Test failure when the token audience isn't accepted
+		{
+			payload:  fmt.Sprintf(`{"iss": "%s", "aud": "other", "sub": "project_path:group/app"}`, gitlabIssuer),
+			expected: &Expected{Rejections: 3, ErrorType: ErrNoMatchingProvider},
+		},
+		// Test failure when the STS audience targets another provider
+		{
+			payload:  fmt.Sprintf(`{"iss": "%s", "aud": "wif", "sub": "repo:octo-org/app", "repository_owner": "octo-org"}`, githubIssuer),
+			audience: gitlabAudience,
+			expected: &Expected{Rejections: 3, ErrorType: ErrNoMatchingProvider},
+		},
+		// Test failure when the pool is disabled
+		{
+			payload:  fmt.Sprintf(`{"iss": "%s", "aud": "wif", "sub": "repo:octo-org/app"}`, githubIssuer),
+			disabled: true,
+			expected: &Expected{ErrorType: ErrPoolDisabled},
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			p := newPool()
+			p.Disabled = tc.disabled
+
+			res, err := p.Route(tc.payload, tc.audience)
+
+			if tc.expected.ErrorType != nil {
+				if !errors.Is(err, tc.expected.ErrorType) {
+					t.Fatalf("Route(%s) = %v, expected %s", tc.payload, err, tc.expected.ErrorType)
+				}
+			} else if err != nil {
+				t.Fatalf("Route(%s) = %s, expected no error", tc.payload, err)
+			}
+
+			if res == nil {
+				return
+			}
+			if (tc.expected.ProviderID == "") != (res.Provider == nil) || (res.Provider != nil && res.Provider.ID != tc.expected.ProviderID) {
+				t.Fatalf("Route(%s) selected %v, expected provider %q", tc.payload, res.Provider, tc.expected.ProviderID)
+			}
+			if (tc.expected.CandidateID == "") != (res.Candidate == nil) || (res.Candidate != nil && res.Candidate.ID != tc.expected.CandidateID) {
+				t.Fatalf("Route(%s) = candidate %v, expected %q", tc.payload, res.Candidate, tc.expected.CandidateID)
+			}
+			if len(res.Rejections) != tc.expected.Rejections {
+				t.Fatalf("Route(%s) = %v, expected %d rejections", tc.payload, res.Rejections, tc.expected.Rejections)
+			}
+		})
+	}
+}
+
+func TestRouteRejectionReasons(t *testing.T) {
+	p := newPool()
+	res, err := p.Route(fmt.Sprintf(`{"iss": "%s", "aud": "wif", "sub": "repo:octo-org/app", "repository_owner": "octo-org"}`, githubIssuer), "")
+
+	if err != nil {
+		t.Fatalf("Route() = %s, expected no error", err)
+	}
+
+	expected := map[string]error{"gitlab": ErrIssuerMismatch, "gitlab-old": ErrProviderDisabled}
+	for _, r := range res.Rejections {
+		if !errors.Is(r, expected[r.ProviderID]) {
+			t.Fatalf("rejection of %s = %s, expected %s", r.ProviderID, r.Err, expected[r.ProviderID])
+		}
+	}
+}
+
+func TestProviderVerify(t *testing.T) {
+	tests := []struct {
+		oidc     *OIDC
+		payload  string
+		expected error
+		isError  bool
+	}{
+		{
+			oidc:    &OIDC{IssuerURI: githubIssuer, AllowedAudiences: []string{"wif"}},
+			payload: `{"iss": "https://token.actions.githubusercontent.com", "aud": "wif"}`,
+		},
+		// unset settings aren't checked
+		{
+			oidc:    &OIDC{},
+			payload: `{"sub": "alice"}`,
+		},
+		{
+			payload: `{"sub": "alice"}`,
+		},
+		{
+			oidc:     &OIDC{IssuerURI: githubIssuer},
+			payload:  `{"iss": "https://gitlab.com", "aud": "wif"}`,
+			expected: ErrIssuerMismatch,
+		},
+		{
+			oidc:     &OIDC{AllowedAudiences: []string{"wif", "sts"}},
+			payload:  `{"iss": "https://gitlab.com", "aud": ["other"]}`,
+			expected: ErrTokenAudienceMismatch,
+		},
+		// only a JWT can be verified
+		{
+			oidc:    &OIDC{JWKSJSON: `{"keys": []}`},
+			payload: `{"sub": "alice"}`,
+			isError: true,
+		},
+		{
+			oidc:    &OIDC{},
+			payload: `not-a-token`,
+			isError: true,
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			p := &Provider{ID: "playground", Type: provider.OIDC, OIDC: tc.oidc}
+			payload, err := p.Verify(tc.payload)
+
+			switch {
+			case tc.expected != nil:
+				if !errors.Is(err, tc.expected) {
+					t.Fatalf("Verify(%s) = %v, expected %s", tc.payload, err, tc.expected)
+				}
+			case tc.isError:
+				if err == nil {
+					t.Fatalf("Verify(%s) -> expect exception", tc.payload)
+				}
+			case err != nil:
+				t.Fatalf("Verify(%s) = %s, expected no error", tc.payload, err)
+			case payload != tc.payload:
+				t.Fatalf("Verify(%s) = %s, expected the payload", tc.payload, payload)
+			}
+		})
+	}
+}
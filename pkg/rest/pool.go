@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/loicsikidi/wif-go/pkg/resource"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	poolNameRegexp     = regexp.MustCompile(`^projects/([^/]+)/locations/global/workloadIdentityPools/([^/]+)$`)
+	providerNameRegexp = regexp.MustCompile(`^projects/([^/]+)/locations/global/workloadIdentityPools/([^/]+)/providers/([^/]+)$`)
+)
+
+// Pool is the REST representation of a Workload Identity Pool
+//
+// (See more at https://cloud.google.com/iam/docs/reference/rest/v1/projects.locations.workloadIdentityPools)
+type Pool struct {
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	State       string `json:"state,omitempty" yaml:"state,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// ParsePool decodes a pool from its JSON or YAML representation
+// (eg. the output of 'gcloud iam workload-identity-pools describe')
+func ParsePool(data []byte) (*Pool, error) {
+	p := &Pool{}
+
+	// YAML is a superset of JSON
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("error decoding pool: %w", err)
+	}
+	return p, nil
+}
+
+// NewPool returns the REST representation of a pool
+func NewPool(pool *resource.Pool) *Pool {
+	return &Pool{
+		Name:        pool.Name(),
+		DisplayName: pool.DisplayName,
+		Description: pool.Description,
+		Disabled:    pool.Disabled,
+	}
+}
+
+// Resource converts the REST representation into a pool
+func (p *Pool) Resource() (*resource.Pool, error) {
+	match := poolNameRegexp.FindStringSubmatch(p.Name)
+
+	if match == nil {
+		return nil, fmt.Errorf("invalid pool name %q: expected 'projects/<number>/locations/global/workloadIdentityPools/<id>'", p.Name)
+	}
+
+	return &resource.Pool{
+		ProjectNumber: match[1],
+		ID:            match[2],
+		DisplayName:   p.DisplayName,
+		Description:   p.Description,
+		Disabled:      p.Disabled,
+	}, nil
+}
+
+// JSON encodes the pool as returned by the REST API
+func (p *Pool) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// YAML encodes the pool as returned by gcloud
+func (p *Pool) YAML() ([]byte, error) {
+	return yaml.Marshal(p)
+}
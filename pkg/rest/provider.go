@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/resource"
+	"gopkg.in/yaml.v3"
+)
+
+// OIDC is the REST representation of an OIDC provider configuration
+type OIDC struct {
+	IssuerURI        string   `json:"issuerUri,omitempty" yaml:"issuerUri,omitempty"`
+	AllowedAudiences []string `json:"allowedAudiences,omitempty" yaml:"allowedAudiences,omitempty"`
+	JWKSJSON         string   `json:"jwksJson,omitempty" yaml:"jwksJson,omitempty"`
+}
+
+// AWS is the REST representation of an AWS provider configuration
+type AWS struct {
+	AccountID string `json:"accountId,omitempty" yaml:"accountId,omitempty"`
+}
+
+// SAML is the REST representation of a SAML provider configuration
+type SAML struct {
+	IdPMetadataXML string `json:"idpMetadataXml,omitempty" yaml:"idpMetadataXml,omitempty"`
+}
+
+// Provider is the REST representation of a Workload Identity Pool Provider
+//
+// (See more at https://cloud.google.com/iam/docs/reference/rest/v1/projects.locations.workloadIdentityPools.providers)
+type Provider struct {
+	Name               string            `json:"name,omitempty" yaml:"name,omitempty"`
+	DisplayName        string            `json:"displayName,omitempty" yaml:"displayName,omitempty"`
+	Description        string            `json:"description,omitempty" yaml:"description,omitempty"`
+	State              string            `json:"state,omitempty" yaml:"state,omitempty"`
+	Disabled           bool              `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	AttributeMapping   map[string]string `json:"attributeMapping,omitempty" yaml:"attributeMapping,omitempty"`
+	AttributeCondition string            `json:"attributeCondition,omitempty" yaml:"attributeCondition,omitempty"`
+	OIDC               *OIDC             `json:"oidc,omitempty" yaml:"oidc,omitempty"`
+	AWS                *AWS              `json:"aws,omitempty" yaml:"aws,omitempty"`
+	SAML               *SAML             `json:"saml,omitempty" yaml:"saml,omitempty"`
+}
+
+// ParseProvider decodes a provider from its JSON or YAML representation
+// (eg. the output of 'gcloud iam workload-identity-pools providers describe')
+func ParseProvider(data []byte) (*Provider, error) {
+	p := &Provider{}
+
+	// YAML is a superset of JSON
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("error decoding provider: %w", err)
+	}
+
+	if err := p.validateBackend(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// validateBackend checks that at most one backend block is set, Google Cloud Platform rejects the others
+func (p *Provider) validateBackend() error {
+	count := 0
+	for _, set := range []bool{p.OIDC != nil, p.AWS != nil, p.SAML != nil} {
+		if set {
+			count++
+		}
+	}
+
+	if count > 1 {
+		return fmt.Errorf("provider %q: only one of 'oidc', 'aws' or 'saml' can be set", p.Name)
+	}
+	return nil
+}
+
+// NewProvider returns the REST representation of a provider attached to a pool
+func NewProvider(pool *resource.Pool, p *resource.Provider) *Provider {
+	r := &Provider{
+		Name:               pool.ProviderName(p.ID),
+		DisplayName:        p.DisplayName,
+		Description:        p.Description,
+		Disabled:           p.Disabled,
+		AttributeMapping:   p.AttributeMapping,
+		AttributeCondition: p.AttributeCondition,
+	}
+
+	switch {
+	case p.Type == provider.OIDC && p.OIDC != nil:
+		r.OIDC = &OIDC{
+			IssuerURI:        p.OIDC.IssuerURI,
+			AllowedAudiences: p.OIDC.AllowedAudiences,
+			JWKSJSON:         p.OIDC.JWKSJSON,
+		}
+	case p.Type == provider.AWS && p.AWS != nil:
+		r.AWS = &AWS{AccountID: p.AWS.AccountID}
+	case p.Type == provider.SAML && p.SAML != nil:
+		r.SAML = &SAML{IdPMetadataXML: p.SAML.IdPMetadataXML}
+	}
+	return r
+}
+
+// Resource converts the REST representation into a provider and the pool it belongs to.
+// The returned pool only holds the identifiers found in the provider name.
+func (p *Provider) Resource() (*resource.Pool, *resource.Provider, error) {
+	match := providerNameRegexp.FindStringSubmatch(p.Name)
+
+	if match == nil {
+		return nil, nil, fmt.Errorf("invalid provider name %q: expected 'projects/<number>/locations/global/workloadIdentityPools/<pool_id>/providers/<id>'", p.Name)
+	}
+
+	if err := p.validateBackend(); err != nil {
+		return nil, nil, err
+	}
+
+	pool := &resource.Pool{
+		ProjectNumber: match[1],
+		ID:            match[2],
+	}
+
+	r := &resource.Provider{
+		ID:                 match[3],
+		DisplayName:        p.DisplayName,
+		Description:        p.Description,
+		Disabled:           p.Disabled,
+		AttributeMapping:   p.AttributeMapping,
+		AttributeCondition: p.AttributeCondition,
+	}
+
+	switch {
+	case p.OIDC != nil:
+		r.Type = provider.OIDC
+		r.OIDC = &resource.OIDC{
+			IssuerURI:        p.OIDC.IssuerURI,
+			AllowedAudiences: p.OIDC.AllowedAudiences,
+			JWKSJSON:         p.OIDC.JWKSJSON,
+		}
+	case p.AWS != nil:
+		r.Type = provider.AWS
+		r.AWS = &resource.AWS{AccountID: p.AWS.AccountID}
+	case p.SAML != nil:
+		r.Type = provider.SAML
+		r.SAML = &resource.SAML{IdPMetadataXML: p.SAML.IdPMetadataXML}
+	default:
+		return nil, nil, fmt.Errorf("provider %q: one of 'oidc', 'aws' or 'saml' is required", p.Name)
+	}
+
+	pool.Providers = []*resource.Provider{r}
+	return pool, r, nil
+}
+
+// JSON encodes the provider as returned by the REST API
+func (p *Provider) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// YAML encodes the provider as returned by gcloud
+func (p *Provider) YAML() ([]byte, error) {
+	return yaml.Marshal(p)
+}
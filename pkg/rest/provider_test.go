@@ -0,0 +1,162 @@
+package rest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+const (
+	gcloudProvider = `attributeCondition: assertion.repository_owner == 'octo-org'
+attributeMapping:
+  attribute.repository: assertion.repository
+  google.subject: assertion.sub
+name: projects/123456789012/locations/global/workloadIdentityPools/ci-pool/providers/github
+oidc:
+  allowedAudiences:
+  - wif
+  issuerUri: https://token.actions.githubusercontent.com
+state: ACTIVE
+`
+	restProvider = `{
+  "name": "projects/123456789012/locations/global/workloadIdentityPools/ci-pool/providers/aws-prod",
+  "state": "ACTIVE",
+  "disabled": true,
+  "aws": {
+    "accountId": "123456789012"
+  }
+}`
+)
+
+func TestParseProvider(t *testing.T) {
+	p, err := ParseProvider([]byte(gcloudProvider))
+
+	if err != nil {
+		t.Fatalf("ParseProvider() = %s, expected no error", err)
+	}
+
+	pool, r, err := p.Resource()
+
+	if err != nil {
+		t.Fatalf("Resource() = %s, expected no error", err)
+	}
+	if pool.ProjectNumber != "123456789012" || pool.ID != "ci-pool" || r.ID != "github" {
+		t.Fatalf("Resource() = %+v, %+v, unexpected identifiers", pool, r)
+	}
+	if r.Type != provider.OIDC || r.OIDC.IssuerURI != "https://token.actions.githubusercontent.com" || !reflect.DeepEqual(r.OIDC.AllowedAudiences, []string{"wif"}) {
+		t.Fatalf("Resource() = %+v, unexpected OIDC configuration", r.OIDC)
+	}
+	if r.AttributeMapping[compiler.GoogleSubject] != "assertion.sub" || r.AttributeCondition != "assertion.repository_owner == 'octo-org'" {
+		t.Fatalf("Resource() = %+v, unexpected mapping or condition", r)
+	}
+	if err := pool.Validate(); err != nil {
+		t.Fatalf("Validate() = %s, expected no error", err)
+	}
+
+	p, err = ParseProvider([]byte(restProvider))
+
+	if err != nil {
+		t.Fatalf("ParseProvider() = %s, expected no error", err)
+	}
+
+	_, r, err = p.Resource()
+
+	if err != nil {
+		t.Fatalf("Resource() = %s, expected no error", err)
+	}
+	if r.Type != provider.AWS || r.AWS.AccountID != "123456789012" || !r.Disabled {
+		t.Fatalf("Resource() = %+v, unexpected AWS provider", r)
+	}
+}
+
+func TestProviderRoundTrip(t *testing.T) {
+	for i, tst := range []string{gcloudProvider, restProvider} {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			p, err := ParseProvider([]byte(tc))
+
+			if err != nil {
+				t.Fatalf("ParseProvider() = %s, expected no error", err)
+			}
+
+			pool, r, err := p.Resource()
+
+			if err != nil {
+				t.Fatalf("Resource() = %s, expected no error", err)
+			}
+
+			// state is an output only field which isn't part of the resource model
+			p.State = ""
+			got := NewProvider(pool, r)
+
+			if !reflect.DeepEqual(got, p) {
+				t.Fatalf("NewProvider() = %+v, expected %+v", got, p)
+			}
+
+			for _, encode := range []func() ([]byte, error){got.JSON, got.YAML} {
+				data, err := encode()
+
+				if err != nil {
+					t.Fatalf("encoding failed: %s", err)
+				}
+
+				decoded, err := ParseProvider(data)
+
+				if err != nil {
+					t.Fatalf("ParseProvider(%s) = %s, expected no error", data, err)
+				}
+				if !reflect.DeepEqual(decoded, got) {
+					t.Fatalf("ParseProvider(%s) = %+v, expected %+v", data, decoded, got)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidProvider(t *testing.T) {
+	tests := []string{
+		`name: projects/123456789012/locations/global/workloadIdentityPools/ci-pool`,
+		`name: projects/123456789012/locations/global/workloadIdentityPools/ci-pool/providers/github`,
+		`attributeMapping: [assertion.sub]`,
+		// several backend blocks are set
+		"name: projects/123456789012/locations/global/workloadIdentityPools/ci-pool/providers/github\noidc: {issuerUri: 'https://token.actions.githubusercontent.com'}\naws: {accountId: '123456789012'}",
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			p, err := ParseProvider([]byte(tc))
+
+			if err == nil {
+				_, _, err = p.Resource()
+			}
+			if err == nil {
+				t.Fatalf("ParseProvider(%s) -> expect exception", tc)
+			}
+		})
+	}
+}
+
+func TestPoolRoundTrip(t *testing.T) {
+	p, err := ParsePool([]byte(`{"name": "projects/123456789012/locations/global/workloadIdentityPools/ci-pool", "displayName": "CI pool", "state": "ACTIVE"}`))
+
+	if err != nil {
+		t.Fatalf("ParsePool() = %s, expected no error", err)
+	}
+
+	pool, err := p.Resource()
+
+	if err != nil {
+		t.Fatalf("Resource() = %s, expected no error", err)
+	}
+	if pool.ID != "ci-pool" || pool.DisplayName != "CI pool" {
+		t.Fatalf("Resource() = %+v, unexpected pool", pool)
+	}
+
+	if got := NewPool(pool); got.Name != p.Name || got.DisplayName != p.DisplayName {
+		t.Fatalf("NewPool() = %+v, expected %+v", got, p)
+	}
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+)
+
+// Routes of the HTTP API.
+const (
+	EvaluatePath      = "/api/v1/evaluate"
+	EvaluateBatchPath = "/api/v1/evaluate:batch"
+	SharesPath        = "/api/v1/shares"
+)
+
+// ErrorCategoryNotFound means that the requested resource doesn't exist.
+const ErrorCategoryNotFound = "not_found"
+
+// ErrorCategoryQuotaExceeded means that the server can't store more resources (eg. shares).
+const ErrorCategoryQuotaExceeded = "quota_exceeded"
+
+// ErrorResponse is the response of a failed request (other than an evaluation)
+type ErrorResponse struct {
+	Error *Error `json:"error"`
+}
+
+// API serves the HTTP API
+type API struct {
+	mux       *http.ServeMux
+	shares    ShareStore
+	metrics   *Metrics
+	observers []Observer
+}
+
+// NewAPI returns the HTTP API, shares are disabled when the store is nil
+// and requests are recorded by metrics when not nil.
+// Observers (eg. an Auditor) are notified of each evaluation.
+func NewAPI(shares ShareStore, metrics *Metrics, others ...Observer) *API {
+	a := &API{mux: http.NewServeMux(), shares: shares, metrics: metrics, observers: observers(metrics, others)}
+	a.handle(EvaluatePath, EvaluatePath, a.handleEvaluate)
+	a.handle(EvaluateBatchPath, EvaluateBatchPath, a.handleEvaluateBatch)
+	if shares != nil {
+		a.handle(SharesPath, SharesPath, a.handleCreateShare)
+		a.handle(SharesPath+"/", SharesPath+"/{id}", a.handleGetShare)
+	}
+	return a
+}
+
+// handle registers the handler of a route, name is the route in the metrics
+func (a *API) handle(pattern, name string, h http.HandlerFunc) {
+	a.mux.Handle(pattern, a.metrics.Instrument(name, h))
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// writeJSON encodes a response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError encodes an error response
+func writeError(w http.ResponseWriter, status int, category string, err error) {
+	writeJSON(w, status, &ErrorResponse{Error: &Error{Category: category, Message: err.Error()}})
+}
+
+// decodeJSON decodes the body of a POST request, limited to limit bytes.
+// The response is written when it fails.
+func decodeJSON(w http.ResponseWriter, r *http.Request, limit int, v any) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, ErrorCategoryInvalidRequest, fmt.Errorf("method %s is not allowed", r.Method))
+		return false
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, int64(limit)))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrorCategoryInvalidRequest, fmt.Errorf("the request exceeds the %d bytes limit", limit))
+		} else {
+			writeError(w, http.StatusBadRequest, ErrorCategoryInvalidRequest, fmt.Errorf("error decoding request: %w", err))
+		}
+		return false
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, ErrorCategoryInvalidRequest, fmt.Errorf("the request must hold a single JSON object"))
+		return false
+	}
+	return true
+}
+
+// statusOf returns the HTTP status of an evaluation
+func statusOf(resp *EvaluateResponse) int {
+	switch {
+	case resp.Error == nil, resp.Error.Category == compiler.CategoryConditionFailed:
+		return http.StatusOK
+	case resp.Error.Category == ErrorCategoryInvalidRequest:
+		return http.StatusBadRequest
+	case resp.Error.Category == compiler.CategoryInternal:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+// handleEvaluate evaluates a token.
+//
+// It responds with 200 when the token is accepted or rejected by the attribute condition,
+// 422 when the configuration or the token is invalid and 400/413 when the request is malformed.
+func (a *API) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	req := &EvaluateRequest{}
+
+	if !decodeJSON(w, r, MaximumRequestLengthInBytes, req) {
+		return
+	}
+
+	resp := observe(TransportHTTP, req, a.observers)
+	writeJSON(w, statusOf(resp), resp)
+}
+
+// handleEvaluateBatch evaluates a batch of tokens.
+//
+// It responds with 200 and the outcome of each token, or 400/413 when the request is malformed.
+func (a *API) handleEvaluateBatch(w http.ResponseWriter, r *http.Request) {
+	req := &BatchEvaluateRequest{}
+
+	if !decodeJSON(w, r, MaximumBatchRequestLengthInBytes, req) {
+		return
+	}
+
+	resp, err := observeBatch(TransportHTTP, req, a.observers)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCategoryInvalidRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleCreateShare stores a playground state and responds with its ID.
+// Sharing the same state again extends the lifetime of the share.
+func (a *API) handleCreateShare(w http.ResponseWriter, r *http.Request) {
+	req := &ShareRequest{}
+
+	if !decodeJSON(w, r, MaximumRequestLengthInBytes, req) {
+		return
+	}
+
+	share, err := NewShare(req, time.Now())
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCategoryInvalidRequest, err)
+		return
+	}
+
+	existing, err := a.shares.Get(share.ID)
+
+	switch {
+	case errors.Is(err, ErrShareNotFound):
+	case err != nil:
+		log.Printf("error reading share '%s': %s", share.ID, err)
+		writeError(w, http.StatusInternalServerError, compiler.CategoryInternal, fmt.Errorf("error reading share"))
+		return
+	case !existing.Expired(share.CreatedAt):
+		share.CreatedAt = existing.CreatedAt
+		if existing.ExpiresAt == nil || share.ExpiresAt != nil && existing.ExpiresAt.After(*share.ExpiresAt) {
+			share.ExpiresAt = existing.ExpiresAt
+		}
+	}
+
+	err = a.shares.Put(share)
+
+	if errors.Is(err, ErrShareQuotaExceeded) {
+		writeError(w, http.StatusInsufficientStorage, ErrorCategoryQuotaExceeded, fmt.Errorf("the server can't store more shares"))
+		return
+	} else if err != nil {
+		log.Printf("error storing share '%s': %s", share.ID, err)
+		writeError(w, http.StatusInternalServerError, compiler.CategoryInternal, fmt.Errorf("error storing share"))
+		return
+	}
+
+	w.Header().Set("Location", SharesPath+"/"+share.ID)
+	writeJSON(w, http.StatusCreated, share)
+}
+
+// handleGetShare responds with a stored playground state
+func (a *API) handleGetShare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, ErrorCategoryInvalidRequest, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, SharesPath+"/")
+	share, err := a.shares.Get(id)
+
+	if err == nil && share.Expired(time.Now()) {
+		if err := a.shares.Delete(id); err != nil {
+			log.Printf("error deleting expired share '%s': %s", id, err)
+		}
+		err = ErrShareNotFound
+	}
+
+	switch {
+	case errors.Is(err, ErrShareNotFound):
+		writeError(w, http.StatusNotFound, ErrorCategoryNotFound, fmt.Errorf("share '%s' doesn't exist or has expired", id))
+	case err != nil:
+		log.Printf("error reading share '%s': %s", id, err)
+		writeError(w, http.StatusInternalServerError, compiler.CategoryInternal, fmt.Errorf("error reading share"))
+	default:
+		writeJSON(w, http.StatusOK, share)
+	}
+}
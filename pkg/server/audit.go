@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/loicsikidi/wif-go/pkg/token"
+	"golang.org/x/exp/slog"
+)
+
+// Actions of an audit policy, applied to the claim values and the attribute values.
+const (
+	// AuditKeep logs the value as is.
+	AuditKeep = "keep"
+	// AuditHash logs a hash of the value, so that identical values can be correlated.
+	AuditHash = "hash"
+	// AuditRedact logs a placeholder instead of the value.
+	AuditRedact = "redact"
+)
+
+// redacted replaces the redacted values
+const redacted = "[REDACTED]"
+
+// hashLength is the number of bytes of a hash which are logged
+const hashLength = 16
+
+// AuditPolicy tells how the claim values and the attribute values are logged
+type AuditPolicy struct {
+	// Default is the action applied to the values without a dedicated action (eg. AuditRedact).
+	Default string
+	// Actions are the dedicated actions, by claim name (eg. iss) or attribute name (eg. google.subject).
+	Actions map[string]string
+	// Key is the HMAC key of the hashes. Without a key, hashes of guessable values (eg. an email) can be reversed by brute force.
+	Key []byte
+}
+
+// ParseAuditPolicy parses a policy such as "redact,iss=keep,sub=hash":
+// a default action optionally followed by the dedicated actions
+func ParseAuditPolicy(spec string) (*AuditPolicy, error) {
+	p := &AuditPolicy{Default: AuditRedact, Actions: map[string]string{}}
+
+	for i, rule := range strings.Split(spec, ",") {
+		rule = strings.TrimSpace(rule)
+		name, action, dedicated := strings.Cut(rule, "=")
+		if !dedicated {
+			action = name
+		}
+
+		if action != AuditKeep && action != AuditHash && action != AuditRedact {
+			return nil, fmt.Errorf("invalid audit action %q: expected %s, %s or %s", action, AuditKeep, AuditHash, AuditRedact)
+		}
+
+		switch {
+		case dedicated && name == "":
+			return nil, fmt.Errorf("invalid audit rule %q: the name is required", rule)
+		case dedicated:
+			p.Actions[name] = action
+		case i == 0:
+			p.Default = action
+		default:
+			return nil, fmt.Errorf("invalid audit rule %q: only the first rule is a default action", rule)
+		}
+	}
+	return p, nil
+}
+
+// action returns the action applied to the value of a claim or an attribute
+func (p *AuditPolicy) action(name string) string {
+	if action, ok := p.Actions[name]; ok {
+		return action
+	}
+	return p.Default
+}
+
+// apply returns the value to log
+func (p *AuditPolicy) apply(name string, value any) any {
+	switch p.action(name) {
+	case AuditKeep:
+		return value
+	case AuditHash:
+		return p.hash(value)
+	default:
+		return redacted
+	}
+}
+
+// hash returns a truncated (HMAC-)SHA-256 of a value, non-string values are hashed from their JSON encoding
+func (p *AuditPolicy) hash(value any) string {
+	s, ok := value.(string)
+	if !ok {
+		b, _ := json.Marshal(value)
+		s = string(b)
+	}
+
+	if len(p.Key) == 0 {
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:hashLength])
+	}
+
+	mac := hmac.New(sha256.New, p.Key)
+	mac.Write([]byte(s))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)[:hashLength])
+}
+
+// applyAll returns the values to log, by name
+func (p *AuditPolicy) applyAll(values map[string]any) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(values))
+	for _, name := range sortedKeys(values) {
+		attrs = append(attrs, slog.Any(name, p.apply(name, values[name])))
+	}
+	return attrs
+}
+
+// Auditor logs each evaluation as a JSON record, without the raw token:
+// the claim values and the attribute values are logged according to a policy
+type Auditor struct {
+	logger *slog.Logger
+	policy *AuditPolicy
+}
+
+// NewAuditor returns an auditor writing to w
+func NewAuditor(w io.Writer, policy *AuditPolicy) *Auditor {
+	return &Auditor{logger: slog.New(slog.NewJSONHandler(w)), policy: policy}
+}
+
+// ConfigHash identifies a configuration (ie. the provider, the attribute mapping and the attribute condition)
+// without logging it: identical configurations share the same hash
+func ConfigHash(req *EvaluateRequest) string {
+	b, _ := json.Marshal(struct {
+		Provider           string            `json:"provider"`
+		AttributeMapping   map[string]string `json:"attributeMapping"`
+		AttributeCondition string            `json:"attributeCondition"`
+	}{providerLabel(req), req.AttributeMapping, req.AttributeCondition})
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// claims returns the top-level claims of the token, nil when the token can't be decoded
+func claims(req *EvaluateRequest) map[string]any {
+	payload, err := token.Payload(req.Payload)
+
+	if err != nil {
+		return nil
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal([]byte(payload), &claims); err != nil {
+		return nil
+	}
+	return claims
+}
+
+// ObserveEvaluation logs an evaluation
+func (a *Auditor) ObserveEvaluation(e *Evaluation) {
+	if a == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("transport", e.Transport),
+		slog.String("provider", providerLabel(e.Request)),
+		slog.String("config_hash", ConfigHash(e.Request)),
+		slog.String("decision", outcomeOf(e.Response)),
+	}
+
+	if e.Response.Error != nil {
+		attrs = append(attrs, slog.String("error_category", e.Response.Error.Category))
+	}
+
+	if c := claims(e.Request); c != nil {
+		attrs = append(attrs, slog.Attr{Key: "claims", Value: slog.GroupValue(a.policy.applyAll(c)...)})
+	}
+
+	if len(e.Response.Attributes) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "attributes", Value: slog.GroupValue(a.policy.applyAll(e.Response.Attributes)...)})
+	}
+
+	attrs = append(attrs, slog.Duration("duration", e.Duration))
+	a.logger.LogAttrs(context.Background(), slog.LevelInfo, "evaluation", attrs...)
+}
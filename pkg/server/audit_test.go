@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseAuditPolicy(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected *AuditPolicy
+	}{
+		{
+			spec:     "redact",
+			expected: &AuditPolicy{Default: AuditRedact, Actions: map[string]string{}},
+		},
+		{
+			spec:     "hash, iss=keep,google.subject=redact",
+			expected: &AuditPolicy{Default: AuditHash, Actions: map[string]string{"iss": AuditKeep, "google.subject": AuditRedact}},
+		},
+		{
+			spec:     "sub=keep",
+			expected: &AuditPolicy{Default: AuditRedact, Actions: map[string]string{"sub": AuditKeep}},
+		},
+		{
+			spec: "",
+		},
+		{
+			spec: "encrypt",
+		},
+		{
+			spec: "keep,=hash",
+		},
+		{
+			spec: "iss=keep,hash",
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			p, err := ParseAuditPolicy(tc.spec)
+
+			if tc.expected == nil {
+				if err == nil {
+					t.Fatalf("ParseAuditPolicy(%q) -> expect exception", tc.spec)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseAuditPolicy(%q) = %s, expected no error", tc.spec, err)
+			}
+
+			if got, expected := fmt.Sprint(p), fmt.Sprint(tc.expected); got != expected {
+				t.Fatalf("ParseAuditPolicy(%q) = %s, expected %s", tc.spec, got, expected)
+			}
+		})
+	}
+}
+
+func TestAuditor(t *testing.T) {
+	policy, _ := ParseAuditPolicy("redact,iss=keep,sub=hash,google.subject=hash")
+	var out bytes.Buffer
+	api := NewAPI(nil, nil, NewAuditor(&out, policy))
+
+	mapping := `"attributeMapping": {"google.subject": "assertion.sub", "attribute.email": "assertion.email"}`
+	for _, body := range []string{
+		`{"payload": "{\"iss\": \"https://issuer\", \"sub\": \"alice\", \"email\": \"alice@example.com\"}", ` + mapping + `}`,
+		`{"payload": "{\"iss\": \"https://issuer\", \"sub\": \"alice\", \"email\": \"alice@example.com\"}", ` + mapping + `, "attributeCondition": "false"}`,
+	} {
+		api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, EvaluatePath, strings.NewReader(body)))
+	}
+
+	if strings.Contains(out.String(), "alice") {
+		t.Fatalf("audit log = %s, expected no raw claim value", out.String())
+	}
+
+	type record struct {
+		Msg           string            `json:"msg"`
+		Transport     string            `json:"transport"`
+		Provider      string            `json:"provider"`
+		ConfigHash    string            `json:"config_hash"`
+		Decision      string            `json:"decision"`
+		ErrorCategory string            `json:"error_category"`
+		Claims        map[string]string `json:"claims"`
+		Attributes    map[string]string `json:"attributes"`
+	}
+
+	var records []*record
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		r := &record{}
+		if err := json.Unmarshal([]byte(line), r); err != nil {
+			t.Fatalf("json.Unmarshal(%s) = %s, expected no error", line, err)
+		}
+		records = append(records, r)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("audit log = %d records, expected 2", len(records))
+	}
+
+	accepted, rejected := records[0], records[1]
+	if accepted.Msg != "evaluation" || accepted.Transport != TransportHTTP || accepted.Provider != "oidc" || accepted.Decision != OutcomeAccepted {
+		t.Fatalf("audit log = %+v, expected an accepted evaluation", accepted)
+	}
+
+	if rejected.Decision != OutcomeRejected || rejected.ErrorCategory != "condition_failed" {
+		t.Fatalf("audit log = %+v, expected a rejected evaluation", rejected)
+	}
+
+	if accepted.ConfigHash == "" || accepted.ConfigHash == rejected.ConfigHash {
+		t.Fatalf("config_hash = %s and %s, expected distinct hashes", accepted.ConfigHash, rejected.ConfigHash)
+	}
+
+	sub := policy.hash("alice")
+	if c := accepted.Claims; c["iss"] != "https://issuer" || c["sub"] != sub || c["email"] != redacted {
+		t.Fatalf("claims = %v, expected iss kept, sub hashed and email redacted", c)
+	}
+
+	if a := accepted.Attributes; a["google.subject"] != sub || a["attribute.email"] != redacted {
+		t.Fatalf("attributes = %v, expected google.subject hashed and attribute.email redacted", a)
+	}
+
+	policy.Key = []byte("secret")
+	if keyed := policy.hash("alice"); keyed == sub || !strings.HasPrefix(keyed, "hmac-sha256:") {
+		t.Fatalf("hash() = %s with a key, expected an HMAC", keyed)
+	}
+}
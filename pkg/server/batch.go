@@ -0,0 +1,151 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/token"
+)
+
+// MaximumBatchSize is the maximum number of tokens of a batch evaluation.
+const MaximumBatchSize = 500
+
+// MaximumBatchRequestLengthInBytes is the maximum size of a batch evaluation request
+// (ie. the default maximum size of a gRPC message).
+const MaximumBatchRequestLengthInBytes = 4 * 1024 * 1024
+
+// BatchEvaluateRequest is a batch of tokens to evaluate against the same attribute mapping and attribute condition
+type BatchEvaluateRequest struct {
+	// [Required] Payloads are the tokens, either JWTs or JSON documents holding the claims.
+	Payloads []string `json:"payloads"`
+	// [Optional] Provider is the provider type (oidc, aws or saml), it defaults to oidc.
+	Provider string `json:"provider,omitempty"`
+	// [Required] AttributeMapping maps the token claims to Google Cloud attributes.
+	AttributeMapping map[string]string `json:"attributeMapping"`
+	// [Optional] AttributeCondition accepts or rejects the tokens.
+	AttributeCondition string `json:"attributeCondition,omitempty"`
+}
+
+// BatchEvaluateResponse is the outcome of a batch evaluation
+type BatchEvaluateResponse struct {
+	// Results are the outcomes of the tokens, in the order of the request.
+	Results []*EvaluateResponse `json:"results"`
+	// Accepted is the number of accepted tokens.
+	Accepted int `json:"accepted"`
+	// Rejected is the number of tokens rejected by the attribute condition.
+	Rejected int `json:"rejected"`
+	// Errors is the number of tokens which can't be evaluated.
+	Errors int `json:"errors"`
+}
+
+// request returns the evaluation request of a token of the batch
+func (r *BatchEvaluateRequest) request(payload string) *EvaluateRequest {
+	return &EvaluateRequest{
+		Payload:            payload,
+		Provider:           r.Provider,
+		AttributeMapping:   r.AttributeMapping,
+		AttributeCondition: r.AttributeCondition,
+	}
+}
+
+// Validate checks the size of the batch and the configuration against the limits of Google Cloud Platform.
+// The tokens are checked one by one during the evaluation.
+func (r *BatchEvaluateRequest) Validate() error {
+	if len(r.Payloads) == 0 {
+		return fmt.Errorf("the batch holds no token")
+	}
+	if len(r.Payloads) > MaximumBatchSize {
+		return fmt.Errorf("the batch exceeds the %d tokens limit", MaximumBatchSize)
+	}
+
+	if err := r.request("").Validate(); err != nil {
+		return err
+	}
+
+	_, err := r.request("").Backend()
+	return err
+}
+
+// count adds the outcome of a token to the counts
+func (r *BatchEvaluateResponse) count(resp *EvaluateResponse) {
+	switch outcomeOf(resp) {
+	case OutcomeAccepted:
+		r.Accepted++
+	case OutcomeRejected:
+		r.Rejected++
+	default:
+		r.Errors++
+	}
+}
+
+// BatchEvaluate evaluates a batch of tokens, the expressions are compiled once and the tokens are evaluated concurrently.
+// Errors are reported by token, an error is returned only when the request is invalid (cf. Validate).
+func BatchEvaluate(r *BatchEvaluateRequest) (*BatchEvaluateResponse, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	results := make([]*EvaluateResponse, len(r.Payloads))
+	backend, _ := r.request("").Backend()
+	p, err := provider.New(backend)
+
+	if err != nil {
+		for i := range results {
+			results[i] = newErrorResponse(compiler.CategoryInvalidInput, err)
+		}
+	} else {
+		// tokens which can't be decoded are not evaluated
+		var indexes []int
+		var payloads []string
+		for i, raw := range r.Payloads {
+			if len(raw) > MaximumPayloadLengthInBytes {
+				results[i] = newErrorResponse(ErrorCategoryInvalidRequest, fmt.Errorf("the payload exceeds the %d bytes limit", MaximumPayloadLengthInBytes))
+				continue
+			}
+
+			payload, err := token.Payload(raw)
+
+			if err != nil {
+				results[i] = newErrorResponse(compiler.CategoryInvalidToken, err)
+				continue
+			}
+			indexes = append(indexes, i)
+			payloads = append(payloads, payload)
+		}
+
+		c := &compiler.Compiler{
+			Input: &compiler.Input{
+				AttributeMapping:   r.AttributeMapping,
+				AttributeCondition: r.AttributeCondition,
+			},
+			Provider: p,
+		}
+		for i, result := range c.RunBatch(payloads, 0) {
+			results[indexes[i]] = newResponse(result.Attributes, result.Err)
+		}
+	}
+
+	resp := &BatchEvaluateResponse{Results: results}
+	for _, result := range results {
+		resp.count(result)
+	}
+	return resp, nil
+}
+
+// observeBatch evaluates a batch of tokens and notifies the observers of each token
+func observeBatch(transport string, req *BatchEvaluateRequest, observers []Observer) (*BatchEvaluateResponse, error) {
+	start := time.Now()
+	resp, err := BatchEvaluate(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Since(start) / time.Duration(len(resp.Results))
+	for i, result := range resp.Results {
+		notify(observers, &Evaluation{Transport: transport, Request: req.request(req.Payloads[i]), Response: result, Duration: duration})
+	}
+	return resp, nil
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Config is the configuration of the server.
+// Each setting can be set by a flag (eg. --addr) or an environment variable (eg. WIF_ADDR), flags take precedence.
+type Config struct {
+	// Addr is the listen address of the HTTP server (eg. :8080).
+	Addr string
+	// GRPCAddr is the listen address of the gRPC server, empty to disable it.
+	GRPCAddr string
+	// TLSCertFile and TLSKeyFile enable TLS on both servers when set.
+	TLSCertFile string
+	TLSKeyFile  string
+	// StaticDir is the directory of the playground, the embedded playground is served when empty (if any).
+	StaticDir string
+	// EnableAPI enables the HTTP API and the gRPC server.
+	EnableAPI bool
+	// SharesDir is the directory of the shares, empty to disable shares.
+	SharesDir string
+	// MaxShares and MaxSharesSize are the quota of the shares (ie. their number and their total size in bytes).
+	MaxShares     int
+	MaxSharesSize int64
+	// ReadTimeout and WriteTimeout bound the duration of an HTTP request.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// ShutdownTimeout is the time given to in-flight requests on shutdown.
+	ShutdownTimeout time.Duration
+	// AuditLog is the file of the audit log ("-" for the standard output), empty to disable it.
+	AuditLog string
+	// AuditPolicy tells how the claim values and the attribute values are logged (cf. ParseAuditPolicy).
+	AuditPolicy string
+	// AuditHashKey is the HMAC key of the hashes of the audit log.
+	AuditHashKey string
+}
+
+// TLS tells whether TLS is enabled
+func (c *Config) TLS() bool {
+	return c.TLSCertFile != ""
+}
+
+// Validate checks the consistency of the configuration
+func (c *Config) Validate() error {
+	if c.Addr == "" {
+		return fmt.Errorf("the listen address is required")
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("both --tls-cert and --tls-key are required to enable TLS")
+	}
+
+	for name, d := range map[string]time.Duration{"read-timeout": c.ReadTimeout, "write-timeout": c.WriteTimeout, "shutdown-timeout": c.ShutdownTimeout} {
+		if d <= 0 {
+			return fmt.Errorf("--%s must be positive", name)
+		}
+	}
+
+	if c.SharesDir != "" && (c.MaxShares <= 0 || c.MaxSharesSize <= 0) {
+		return fmt.Errorf("--max-shares and --max-shares-size must be positive")
+	}
+
+	if _, err := c.Audit(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Audit returns the policy of the audit log
+func (c *Config) Audit() (*AuditPolicy, error) {
+	p, err := ParseAuditPolicy(c.AuditPolicy)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid --audit-policy: %w", err)
+	}
+	p.Key = []byte(c.AuditHashKey)
+	return p, nil
+}
+
+// env reads the environment variables of the configuration
+type env struct {
+	lookupEnv func(string) (string, bool)
+	err       error
+}
+
+func (e *env) get(name, fallback string) string {
+	if v, ok := e.lookup(name); ok {
+		return v
+	}
+	return fallback
+}
+
+func (e *env) lookup(name string) (string, bool) {
+	return e.lookupEnv(name)
+}
+
+func (e *env) getBool(name string, fallback bool) bool {
+	v, ok := e.lookup(name)
+	if !ok {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil && e.err == nil {
+		e.err = fmt.Errorf("invalid value %q for %s: %w", v, name, err)
+	}
+	return b
+}
+
+func (e *env) getInt(name string, fallback int64) int64 {
+	v, ok := e.lookup(name)
+	if !ok {
+		return fallback
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil && e.err == nil {
+		e.err = fmt.Errorf("invalid value %q for %s: %w", v, name, err)
+	}
+	return i
+}
+
+func (e *env) getDuration(name string, fallback time.Duration) time.Duration {
+	v, ok := e.lookup(name)
+	if !ok {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil && e.err == nil {
+		e.err = fmt.Errorf("invalid value %q for %s: %w", v, name, err)
+	}
+	return d
+}
+
+// LoadConfig reads the configuration from the command-line arguments and the environment (cf. os.LookupEnv).
+// Usage and parsing errors are written to output.
+func LoadConfig(args []string, lookupEnv func(string) (string, bool), output io.Writer) (*Config, error) {
+	e := &env{lookupEnv: lookupEnv}
+
+	// PORT and KO_DATA_PATH are set by Cloud Run and ko
+	addr := ":8080"
+	if port, ok := e.lookup("PORT"); ok && port != "" {
+		addr = ":" + port
+	}
+
+	c := &Config{}
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&c.Addr, "addr", e.get("WIF_ADDR", addr), "listen address of the HTTP server [$WIF_ADDR]")
+	fs.StringVar(&c.GRPCAddr, "grpc-addr", e.get("WIF_GRPC_ADDR", ":9090"), "listen address of the gRPC server, empty to disable it [$WIF_GRPC_ADDR]")
+	fs.StringVar(&c.TLSCertFile, "tls-cert", e.get("WIF_TLS_CERT", ""), "TLS certificate file [$WIF_TLS_CERT]")
+	fs.StringVar(&c.TLSKeyFile, "tls-key", e.get("WIF_TLS_KEY", ""), "TLS private key file [$WIF_TLS_KEY]")
+	fs.StringVar(&c.StaticDir, "static-dir", e.get("WIF_STATIC_DIR", e.get("KO_DATA_PATH", "")), "directory of the playground, defaults to the embedded one [$WIF_STATIC_DIR]")
+	fs.BoolVar(&c.EnableAPI, "api", e.getBool("WIF_API", true), "enable the HTTP API and the gRPC server [$WIF_API]")
+	fs.StringVar(&c.SharesDir, "shares-dir", e.get("WIF_SHARES_DIR", ""), "directory of the shares, shares are disabled when empty [$WIF_SHARES_DIR]")
+	fs.IntVar(&c.MaxShares, "max-shares", int(e.getInt("WIF_MAX_SHARES", 10000)), "maximum number of shares [$WIF_MAX_SHARES]")
+	fs.Int64Var(&c.MaxSharesSize, "max-shares-size", e.getInt("WIF_MAX_SHARES_SIZE", 100<<20), "maximum total size of the shares in bytes [$WIF_MAX_SHARES_SIZE]")
+	fs.DurationVar(&c.ReadTimeout, "read-timeout", e.getDuration("WIF_READ_TIMEOUT", 15*time.Second), "maximum duration for reading a request [$WIF_READ_TIMEOUT]")
+	fs.DurationVar(&c.WriteTimeout, "write-timeout", e.getDuration("WIF_WRITE_TIMEOUT", 30*time.Second), "maximum duration for writing a response [$WIF_WRITE_TIMEOUT]")
+	fs.DurationVar(&c.ShutdownTimeout, "shutdown-timeout", e.getDuration("WIF_SHUTDOWN_TIMEOUT", 15*time.Second), "time given to in-flight requests on shutdown [$WIF_SHUTDOWN_TIMEOUT]")
+	fs.StringVar(&c.AuditLog, "audit-log", e.get("WIF_AUDIT_LOG", ""), "file of the audit log of the evaluations, - for stdout, empty to disable it [$WIF_AUDIT_LOG]")
+	fs.StringVar(&c.AuditPolicy, "audit-policy", e.get("WIF_AUDIT_POLICY", AuditRedact), "how claim and attribute values are logged, eg. redact,iss=keep,sub=hash [$WIF_AUDIT_POLICY]")
+	fs.StringVar(&c.AuditHashKey, "audit-hash-key", e.get("WIF_AUDIT_HASH_KEY", ""), "HMAC key of the hashed values of the audit log [$WIF_AUDIT_HASH_KEY]")
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
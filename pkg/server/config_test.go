@@ -0,0 +1,98 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	type Expected struct {
+		addr      string
+		grpcAddr  string
+		staticDir string
+		sharesDir string
+		api       bool
+		read      time.Duration
+	}
+	tests := []struct {
+		args     []string
+		env      map[string]string
+		expected *Expected
+	}{
+		{
+			expected: &Expected{addr: ":8080", grpcAddr: ":9090", api: true, read: 15 * time.Second},
+		},
+		{
+			env:      map[string]string{"PORT": "3000", "KO_DATA_PATH": "/var/run/ko", "WIF_API": "false", "WIF_READ_TIMEOUT": "5s"},
+			expected: &Expected{addr: ":3000", grpcAddr: ":9090", staticDir: "/var/run/ko", read: 5 * time.Second},
+		},
+		{
+			args:     []string{"--shares-dir", "/var/lib/wif-go", "--max-shares", "10"},
+			env:      map[string]string{"WIF_MAX_SHARES_SIZE": "1048576"},
+			expected: &Expected{addr: ":8080", grpcAddr: ":9090", sharesDir: "/var/lib/wif-go", api: true, read: 15 * time.Second},
+		},
+		// flags take precedence over environment variables
+		{
+			args:     []string{"--addr", "127.0.0.1:8443", "--static-dir", "dist", "--api=true", "--read-timeout", "1m"},
+			env:      map[string]string{"WIF_ADDR": ":8081", "WIF_GRPC_ADDR": "", "WIF_STATIC_DIR": "public", "WIF_API": "0"},
+			expected: &Expected{addr: "127.0.0.1:8443", staticDir: "dist", api: true, read: time.Minute},
+		},
+		{
+			args: []string{"--tls-cert", "cert.pem"},
+		},
+		{
+			args: []string{"--write-timeout", "0s"},
+		},
+		{
+			env: map[string]string{"WIF_API": "maybe"},
+		},
+		{
+			env: map[string]string{"WIF_SHUTDOWN_TIMEOUT": "soon"},
+		},
+		{
+			env: map[string]string{"WIF_MAX_SHARES": "many"},
+		},
+		{
+			args: []string{"--shares-dir", "/var/lib/wif-go", "--max-shares-size", "0"},
+		},
+		{
+			env: map[string]string{"WIF_AUDIT_POLICY": "hash,sub=encrypt"},
+		},
+		{
+			args: []string{"--unknown"},
+		},
+		{
+			args: []string{"serve"},
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			lookupEnv := func(name string) (string, bool) {
+				v, ok := tc.env[name]
+				return v, ok
+			}
+
+			c, err := LoadConfig(tc.args, lookupEnv, io.Discard)
+
+			if tc.expected == nil {
+				if err == nil {
+					t.Fatalf("LoadConfig(%v) -> expect exception", tc.args)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadConfig(%v) = %s, expected no error", tc.args, err)
+			}
+
+			got := &Expected{addr: c.Addr, grpcAddr: c.GRPCAddr, staticDir: c.StaticDir, sharesDir: c.SharesDir, api: c.EnableAPI, read: c.ReadTimeout}
+			if *got != *tc.expected {
+				t.Fatalf("LoadConfig(%v) = %+v, expected %+v", tc.args, got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/token"
+)
+
+// MaximumPayloadLengthInBytes is the maximum size of a token.
+const MaximumPayloadLengthInBytes = 32 * 1024
+
+// MaximumAttributeMappingEntries is the maximum number of attributes of a mapping
+// (ie. google.subject, google.groups and the custom attributes).
+const MaximumAttributeMappingEntries = compiler.MaximumCustomAttributes + 2
+
+// MaximumRequestLengthInBytes is the maximum size of an evaluation request.
+// It's large enough to hold the biggest valid input, doubled since JSON escaping may inflate strings.
+const MaximumRequestLengthInBytes = 2 * (MaximumPayloadLengthInBytes +
+	MaximumAttributeMappingEntries*(len("attribute.")+compiler.MaximumCustomAttributeNameSize+compiler.MaximumAttributeExpressionLengthInBytes) +
+	compiler.MaximumAttributeConditionLengthInBytes)
+
+// ErrorCategoryInvalidRequest means that the request can't be decoded or exceeds a limit.
+const ErrorCategoryInvalidRequest = "invalid_request"
+
+// EvaluateRequest is a token to evaluate against an attribute mapping and an attribute condition
+type EvaluateRequest struct {
+	// [Required] Payload is the token, either a JWT or a JSON document holding the claims.
+	Payload string `json:"payload"`
+	// [Optional] Provider is the provider type (oidc, aws or saml), it defaults to oidc.
+	Provider string `json:"provider,omitempty"`
+	// [Required] AttributeMapping maps the token claims to Google Cloud attributes.
+	AttributeMapping map[string]string `json:"attributeMapping"`
+	// [Optional] AttributeCondition accepts or rejects the token.
+	AttributeCondition string `json:"attributeCondition,omitempty"`
+}
+
+// Error is an error which occurred while evaluating a request
+type Error struct {
+	// Category of the error (eg. compilation, cf. compiler.ErrorCategory).
+	Category string `json:"category"`
+	// Message describes the error.
+	Message string `json:"message"`
+}
+
+// EvaluateResponse is the outcome of an evaluation
+type EvaluateResponse struct {
+	// Accepted tells whether the token is accepted.
+	Accepted bool `json:"accepted"`
+	// Attributes are the derived attributes of an accepted token.
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// Error tells why the token isn't accepted.
+	Error *Error `json:"error,omitempty"`
+}
+
+func newErrorResponse(category string, err error) *EvaluateResponse {
+	return &EvaluateResponse{Error: &Error{Category: category, Message: err.Error()}}
+}
+
+// Validate checks the request against the limits of Google Cloud Platform
+func (r *EvaluateRequest) Validate() error {
+	if len(r.Payload) > MaximumPayloadLengthInBytes {
+		return fmt.Errorf("the payload exceeds the %d bytes limit", MaximumPayloadLengthInBytes)
+	}
+	if len(r.AttributeMapping) > MaximumAttributeMappingEntries {
+		return fmt.Errorf("the attribute mapping exceeds the %d attributes limit", MaximumAttributeMappingEntries)
+	}
+	for k, v := range r.AttributeMapping {
+		if len(v) > compiler.MaximumAttributeExpressionLengthInBytes {
+			return fmt.Errorf("the expression of '%s' exceeds the %d bytes limit", k, compiler.MaximumAttributeExpressionLengthInBytes)
+		}
+	}
+	if len(r.AttributeCondition) > compiler.MaximumAttributeConditionLengthInBytes {
+		return fmt.Errorf("the attribute condition exceeds the %d bytes limit", compiler.MaximumAttributeConditionLengthInBytes)
+	}
+	return nil
+}
+
+// Backend returns the provider backend of the request
+func (r *EvaluateRequest) Backend() (provider.Backend, error) {
+	if r.Provider == "" {
+		return provider.OIDC, nil
+	}
+	return provider.ParseBackend(r.Provider)
+}
+
+// Compiler returns the compiler evaluating the request
+func (r *EvaluateRequest) Compiler() (*compiler.Compiler, error) {
+	if err := r.Validate(); err != nil {
+		return nil, &compiler.Error{Category: ErrorCategoryInvalidRequest, Err: err}
+	}
+
+	backend, err := r.Backend()
+
+	if err != nil {
+		return nil, &compiler.Error{Category: ErrorCategoryInvalidRequest, Err: err}
+	}
+
+	p, err := provider.New(backend)
+
+	if err != nil {
+		return nil, &compiler.Error{Category: compiler.CategoryInvalidInput, Err: err}
+	}
+
+	payload, err := token.Payload(r.Payload)
+
+	if err != nil {
+		return nil, &compiler.Error{Category: compiler.CategoryInvalidToken, Err: err}
+	}
+
+	return &compiler.Compiler{
+		Input: &compiler.Input{
+			Payload:            payload,
+			AttributeMapping:   r.AttributeMapping,
+			AttributeCondition: r.AttributeCondition,
+		},
+		Provider: p,
+	}, nil
+}
+
+// Evaluate evaluates a request, errors are reported in the response
+func Evaluate(r *EvaluateRequest) *EvaluateResponse {
+	c, err := r.Compiler()
+
+	if err != nil {
+		return newErrorResponse(compiler.ErrorCategory(err), err)
+	}
+
+	return newResponse(c.Run())
+}
+
+// newResponse returns the outcome of an evaluation from the result of the compiler
+func newResponse(attributes map[string]any, err error) *EvaluateResponse {
+	switch {
+	case errors.Is(err, compiler.ErrAttrConditionFailed):
+		return newErrorResponse(compiler.CategoryConditionFailed, err)
+	case err != nil:
+		return newErrorResponse(compiler.ErrorCategory(err), err)
+	default:
+		return &EvaluateResponse{Accepted: true, Attributes: attributes}
+	}
+}
+
+// Transports of an evaluation.
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// Evaluation is an evaluated request
+type Evaluation struct {
+	// Transport of the request (eg. TransportHTTP).
+	Transport string
+	Request   *EvaluateRequest
+	Response  *EvaluateResponse
+	// Duration of the evaluation, the duration of a batch is shared by its tokens.
+	Duration time.Duration
+}
+
+// Observer is notified of each evaluation (eg. Metrics)
+type Observer interface {
+	ObserveEvaluation(e *Evaluation)
+}
+
+// observe evaluates a request and notifies the observers
+func observe(transport string, req *EvaluateRequest, observers []Observer) *EvaluateResponse {
+	start := time.Now()
+	resp := Evaluate(req)
+
+	notify(observers, &Evaluation{Transport: transport, Request: req, Response: resp, Duration: time.Since(start)})
+	return resp
+}
+
+// notify notifies the observers of an evaluation
+func notify(observers []Observer, e *Evaluation) {
+	for _, o := range observers {
+		o.ObserveEvaluation(e)
+	}
+}
+
+// observers returns the observers of the evaluations, metrics included when not nil
+func observers(metrics *Metrics, others []Observer) []Observer {
+	var out []Observer
+	if metrics != nil {
+		out = append(out, metrics)
+	}
+	for _, o := range others {
+		if o != nil {
+			out = append(out, o)
+		}
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	pb "github.com/loicsikidi/wif-go/pkg/generated/protobuf"
+	"github.com/loicsikidi/wif-go/pkg/validate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var providerKinds = map[pb.ProviderKind]string{
+	pb.ProviderKind_PROVIDER_KIND_UNSPECIFIED: "",
+	pb.ProviderKind_PROVIDER_KIND_OIDC:        provider.BackendName(provider.OIDC),
+	pb.ProviderKind_PROVIDER_KIND_AWS:         provider.BackendName(provider.AWS),
+	pb.ProviderKind_PROVIDER_KIND_SAML:        provider.BackendName(provider.SAML),
+}
+
+var severities = map[string]pb.Severity{
+	validate.SeverityError:   pb.Severity_SEVERITY_ERROR,
+	validate.SeverityWarning: pb.Severity_SEVERITY_WARNING,
+}
+
+// WifService implements the gRPC service wifgo.v1beta.WifService
+type WifService struct {
+	pb.UnimplementedWifServiceServer
+
+	observers []Observer
+}
+
+// NewWifService returns the gRPC service, evaluations are recorded by metrics when not nil
+// and observers (eg. an Auditor) are notified of each evaluation
+func NewWifService(metrics *Metrics, others ...Observer) *WifService {
+	return &WifService{observers: observers(metrics, others)}
+}
+
+// providerName returns the name of a provider kind (eg. oidc)
+func providerName(kind pb.ProviderKind) (string, error) {
+	name, ok := providerKinds[kind]
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unknown provider kind %d", kind)
+	}
+	return name, nil
+}
+
+// toProto converts the outcome of an evaluation
+func toProto(resp *EvaluateResponse) (*pb.EvaluateResponse, error) {
+	out := &pb.EvaluateResponse{Accepted: resp.Accepted}
+
+	if resp.Attributes != nil {
+		attributes, err := structpb.NewStruct(resp.Attributes)
+
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error converting attributes to protobuf: %s", err)
+		}
+		out.Attributes = attributes
+	}
+
+	if resp.Error != nil {
+		out.Error = &pb.Error{Category: resp.Error.Category, Message: resp.Error.Message}
+	}
+	return out, nil
+}
+
+// evaluate evaluates a token, a malformed request is reported as an InvalidArgument status
+func (s *WifService) evaluate(req *EvaluateRequest) (*pb.EvaluateResponse, error) {
+	resp := observe(TransportGRPC, req, s.observers)
+
+	if resp.Error != nil && resp.Error.Category == ErrorCategoryInvalidRequest {
+		return nil, status.Error(codes.InvalidArgument, resp.Error.Message)
+	}
+	return toProto(resp)
+}
+
+// Evaluate derives the attributes of a token and checks them against the attribute condition
+func (s *WifService) Evaluate(_ context.Context, req *pb.EvaluateRequest) (*pb.EvaluateResponse, error) {
+	name, err := providerName(req.GetProvider())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.evaluate(&EvaluateRequest{
+		Payload:            req.GetPayload(),
+		Provider:           name,
+		AttributeMapping:   req.GetAttributeMapping(),
+		AttributeCondition: req.GetAttributeCondition(),
+	})
+}
+
+// Validate checks an attribute mapping and an attribute condition without a token
+func (s *WifService) Validate(_ context.Context, req *pb.ValidateRequest) (*pb.ValidateResponse, error) {
+	name, err := providerName(req.GetProvider())
+
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := (&EvaluateRequest{Provider: name}).Backend()
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	p, err := provider.New(backend)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if len(req.GetAttributeMapping()) > MaximumAttributeMappingEntries {
+		return nil, status.Errorf(codes.InvalidArgument, "the attribute mapping exceeds the %d attributes limit", MaximumAttributeMappingEntries)
+	}
+
+	diagnostics := validate.Validate(p, req.GetAttributeMapping(), req.GetAttributeCondition())
+	resp := &pb.ValidateResponse{Valid: validate.Valid(diagnostics)}
+
+	for _, d := range diagnostics {
+		resp.Diagnostics = append(resp.Diagnostics, &pb.Diagnostic{
+			Attribute: d.Attribute,
+			Severity:  severities[d.Severity],
+			Message:   d.Message,
+			Line:      int32(d.Line),
+			Column:    int32(d.Column),
+			EndLine:   int32(d.EndLine),
+			EndColumn: int32(d.EndColumn),
+		})
+	}
+	return resp, nil
+}
+
+// BatchEvaluate evaluates several tokens against the same attribute mapping and attribute condition
+func (s *WifService) BatchEvaluate(_ context.Context, req *pb.BatchEvaluateRequest) (*pb.BatchEvaluateResponse, error) {
+	name, err := providerName(req.GetProvider())
+
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := observeBatch(TransportGRPC, &BatchEvaluateRequest{
+		Payloads:           req.GetPayloads(),
+		Provider:           name,
+		AttributeMapping:   req.GetAttributeMapping(),
+		AttributeCondition: req.GetAttributeCondition(),
+	}, s.observers)
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &pb.BatchEvaluateResponse{
+		Accepted: int32(batch.Accepted),
+		Rejected: int32(batch.Rejected),
+		Errors:   int32(batch.Errors),
+	}
+	for _, result := range batch.Results {
+		out, err := toProto(result)
+
+		if err != nil {
+			return nil, err
+		}
+		resp.Results = append(resp.Results, out)
+	}
+	return resp, nil
+}
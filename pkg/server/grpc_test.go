@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	pb "github.com/loicsikidi/wif-go/pkg/generated/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newClient(t *testing.T) pb.WifServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	pb.RegisterWifServiceServer(s, NewWifService(nil))
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+
+	if err != nil {
+		t.Fatalf("grpc.Dial() = %s, expected no error", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewWifServiceClient(conn)
+}
+
+func TestWifServiceEvaluate(t *testing.T) {
+	type Expected struct {
+		code     codes.Code
+		accepted bool
+		category string
+		subject  string
+	}
+	tests := []struct {
+		req      *pb.EvaluateRequest
+		expected *Expected
+	}{
+		{
+			req: &pb.EvaluateRequest{
+				Payload:            `{"sub": "alice", "org": "octo-org"}`,
+				AttributeMapping:   map[string]string{"google.subject": "assertion.sub"},
+				AttributeCondition: "assertion.org == 'octo-org'",
+			},
+			expected: &Expected{accepted: true, subject: "alice"},
+		},
+		{
+			req: &pb.EvaluateRequest{
+				Provider:           pb.ProviderKind_PROVIDER_KIND_OIDC,
+				Payload:            `{"sub": "alice", "org": "evil-org"}`,
+				AttributeMapping:   map[string]string{"google.subject": "assertion.sub"},
+				AttributeCondition: "assertion.org == 'octo-org'",
+			},
+			expected: &Expected{category: compiler.CategoryConditionFailed},
+		},
+		{
+			req: &pb.EvaluateRequest{
+				Payload:          `{"sub": "alice"}`,
+				AttributeMapping: map[string]string{"google.subject": "assertion.sub +"},
+			},
+			expected: &Expected{category: compiler.CategoryCompilation},
+		},
+		{
+			req: &pb.EvaluateRequest{
+				Provider:         pb.ProviderKind_PROVIDER_KIND_SAML,
+				Payload:          `{"sub": "alice"}`,
+				AttributeMapping: map[string]string{"google.subject": "assertion.sub"},
+			},
+			expected: &Expected{category: compiler.CategoryInvalidInput},
+		},
+		{
+			req: &pb.EvaluateRequest{
+				Provider:         pb.ProviderKind(42),
+				Payload:          `{"sub": "alice"}`,
+				AttributeMapping: map[string]string{"google.subject": "assertion.sub"},
+			},
+			expected: &Expected{code: codes.InvalidArgument},
+		},
+	}
+
+	client := newClient(t)
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			resp, err := client.Evaluate(context.Background(), tc.req)
+
+			got := &Expected{
+				code:     status.Code(err),
+				accepted: resp.GetAccepted(),
+				category: resp.GetError().GetCategory(),
+				subject:  resp.GetAttributes().GetFields()["google.subject"].GetStringValue(),
+			}
+			if *got != *tc.expected {
+				t.Fatalf("Evaluate() = %+v (%v), expected %+v", got, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWifServiceValidate(t *testing.T) {
+	client := newClient(t)
+
+	resp, err := client.Validate(context.Background(), &pb.ValidateRequest{
+		AttributeMapping:   map[string]string{"google.subject": "assertion.sub +"},
+		AttributeCondition: "attribute.role == 'admin'",
+	})
+
+	if err != nil {
+		t.Fatalf("Validate() = %s, expected no error", err)
+	}
+
+	if resp.GetValid() || len(resp.GetDiagnostics()) != 2 {
+		t.Fatalf("Validate() = %v, expected 2 diagnostics", resp)
+	}
+
+	if d := resp.GetDiagnostics()[0]; d.GetAttribute() != "google.subject" || d.GetSeverity() != pb.Severity_SEVERITY_ERROR || d.GetLine() != 1 {
+		t.Fatalf("Validate() = %v, expected a syntax error in google.subject", d)
+	}
+
+	if d := resp.GetDiagnostics()[1]; d.GetAttribute() != "" || d.GetSeverity() != pb.Severity_SEVERITY_WARNING || d.GetColumn() != 1 || d.GetEndColumn() != 15 {
+		t.Fatalf("Validate() = %v, expected a warning in the attribute condition", d)
+	}
+}
+
+func TestWifServiceBatchEvaluate(t *testing.T) {
+	client := newClient(t)
+
+	resp, err := client.BatchEvaluate(context.Background(), &pb.BatchEvaluateRequest{
+		Payloads:           []string{`{"sub": "alice", "org": "octo-org"}`, `{"sub": "bob", "org": "evil-org"}`, `not-a-token`},
+		AttributeMapping:   map[string]string{"google.subject": "assertion.sub"},
+		AttributeCondition: "assertion.org == 'octo-org'",
+	})
+
+	if err != nil {
+		t.Fatalf("BatchEvaluate() = %s, expected no error", err)
+	}
+
+	if resp.GetAccepted() != 1 || resp.GetRejected() != 1 || resp.GetErrors() != 1 || len(resp.GetResults()) != 3 {
+		t.Fatalf("BatchEvaluate() = %v, expected 1 accepted, 1 rejected and 1 error", resp)
+	}
+
+	if category := resp.GetResults()[2].GetError().GetCategory(); category != compiler.CategoryInvalidToken {
+		t.Fatalf("BatchEvaluate() = %s, expected %s", category, compiler.CategoryInvalidToken)
+	}
+
+	_, err = client.BatchEvaluate(context.Background(), &pb.BatchEvaluateRequest{Payloads: make([]string, MaximumBatchSize+1)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("BatchEvaluate() -> expect exception")
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Routes of the health checks.
+const (
+	HealthzPath = "/healthz"
+	ReadyzPath  = "/readyz"
+	MetricsPath = "/metrics"
+)
+
+// Health reports the liveness and the readiness of the server
+type Health struct {
+	ready  atomic.Bool
+	mu     sync.Mutex
+	checks map[string]func() error
+}
+
+// NewHealth returns a health reporter, the server isn't ready until SetReady is called
+func NewHealth() *Health {
+	return &Health{checks: map[string]func() error{}}
+}
+
+// SetReady marks the server as ready (or not, eg. while shutting down)
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// AddCheck adds a readiness check (eg. the shares store is writable)
+func (h *Health) AddCheck(name string, check func() error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// Ready returns an error when the server can't serve requests
+func (h *Health) Ready() error {
+	if !h.ready.Load() {
+		return fmt.Errorf("the server is not ready")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := h.checks[name](); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Handler returns the handler of HealthzPath and ReadyzPath
+func (h *Health) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthzPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc(ReadyzPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := h.Ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
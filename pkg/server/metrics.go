@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Outcomes of an evaluation.
+const (
+	OutcomeAccepted = "accepted"
+	OutcomeRejected = "rejected"
+	OutcomeError    = "error"
+)
+
+// LatencyBuckets are the upper bounds (in seconds) of the evaluation latency histogram.
+var LatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// histogram is a Prometheus histogram
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(v float64) {
+	for i, bound := range LatencyBuckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// Metrics records the activity of the server and exposes it in the Prometheus text format.
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	mu           sync.Mutex
+	httpRequests map[[3]string]uint64
+	grpcRequests map[[2]string]uint64
+	evaluations  map[[2]string]uint64
+	latency      map[string]*histogram
+}
+
+// NewMetrics returns an empty set of metrics
+func NewMetrics() *Metrics {
+	return &Metrics{
+		httpRequests: map[[3]string]uint64{},
+		grpcRequests: map[[2]string]uint64{},
+		evaluations:  map[[2]string]uint64{},
+		latency:      map[string]*histogram{},
+	}
+}
+
+// providerLabel returns the provider of a request, unknown providers share the same label
+func providerLabel(req *EvaluateRequest) string {
+	backend, err := req.Backend()
+
+	if err != nil {
+		return "unknown"
+	}
+	return provider.BackendName(backend)
+}
+
+// outcomeOf returns the outcome of an evaluation (eg. OutcomeAccepted)
+func outcomeOf(resp *EvaluateResponse) string {
+	switch {
+	case resp.Accepted:
+		return OutcomeAccepted
+	case resp.Error != nil && resp.Error.Category == compiler.CategoryConditionFailed:
+		return OutcomeRejected
+	default:
+		return OutcomeError
+	}
+}
+
+// ObserveEvaluation records the outcome and the latency of an evaluation
+func (m *Metrics) ObserveEvaluation(e *Evaluation) {
+	if m == nil {
+		return
+	}
+
+	p := providerLabel(e.Request)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evaluations[[2]string{p, outcomeOf(e.Response)}]++
+	h, ok := m.latency[p]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(LatencyBuckets))}
+		m.latency[p] = h
+	}
+	h.observe(e.Duration.Seconds())
+}
+
+// statusRecorder captures the status code of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Instrument counts the requests served by a handler
+func (m *Metrics) Instrument(name string, h http.Handler) http.Handler {
+	if m == nil {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.httpRequests[[3]string{name, r.Method, strconv.Itoa(rec.status)}]++
+	})
+}
+
+// UnaryInterceptor counts the gRPC requests
+func (m *Metrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+
+		if m != nil {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.grpcRequests[[2]string{info.FullMethod, status.Code(err).String()}]++
+		}
+		return resp, err
+	}
+}
+
+// labels formats the labels of a sample (eg. {provider="oidc"})
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], labelEscaper.Replace(pairs[i+1]))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of a map in a stable order
+func sortedKeys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+// WriteTo writes the metrics in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	if m == nil {
+		return 0, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP wif_http_requests_total Number of HTTP requests.\n# TYPE wif_http_requests_total counter\n")
+	for _, k := range sortedKeys(m.httpRequests) {
+		fmt.Fprintf(&b, "wif_http_requests_total%s %d\n", labels("handler", k[0], "method", k[1], "code", k[2]), m.httpRequests[k])
+	}
+
+	b.WriteString("# HELP wif_grpc_requests_total Number of gRPC requests.\n# TYPE wif_grpc_requests_total counter\n")
+	for _, k := range sortedKeys(m.grpcRequests) {
+		fmt.Fprintf(&b, "wif_grpc_requests_total%s %d\n", labels("method", k[0], "code", k[1]), m.grpcRequests[k])
+	}
+
+	b.WriteString("# HELP wif_evaluations_total Number of evaluated tokens by provider and outcome (accepted, rejected or error).\n# TYPE wif_evaluations_total counter\n")
+	for _, k := range sortedKeys(m.evaluations) {
+		fmt.Fprintf(&b, "wif_evaluations_total%s %d\n", labels("provider", k[0], "outcome", k[1]), m.evaluations[k])
+	}
+
+	b.WriteString("# HELP wif_evaluation_duration_seconds Latency of evaluations by provider.\n# TYPE wif_evaluation_duration_seconds histogram\n")
+	for _, p := range sortedKeys(m.latency) {
+		h := m.latency[p]
+		for i, bound := range LatencyBuckets {
+			fmt.Fprintf(&b, "wif_evaluation_duration_seconds_bucket%s %d\n", labels("provider", p, "le", formatFloat(bound)), h.counts[i])
+		}
+		fmt.Fprintf(&b, "wif_evaluation_duration_seconds_bucket%s %d\n", labels("provider", p, "le", "+Inf"), h.count)
+		fmt.Fprintf(&b, "wif_evaluation_duration_seconds_sum%s %s\n", labels("provider", p), formatFloat(h.sum))
+		fmt.Fprintf(&b, "wif_evaluation_duration_seconds_count%s %d\n", labels("provider", p), h.count)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP exposes the metrics
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	api := NewAPI(nil, metrics)
+
+	for _, body := range []string{
+		`{"payload": "{\"sub\": \"alice\", \"org\": \"octo-org\"}", "attributeMapping": {"google.subject": "assertion.sub"}, "attributeCondition": "assertion.org == 'octo-org'"}`,
+		`{"payload": "{\"sub\": \"bob\", \"org\": \"evil-org\"}", "attributeMapping": {"google.subject": "assertion.sub"}, "attributeCondition": "assertion.org == 'octo-org'"}`,
+		`{"payload": "{\"sub\": \"bob\"}", "provider": "OIDC", "attributeMapping": {"google.subject": "assertion.sub +"}}`,
+		`{"payload": "{}", "provider": "x509", "attributeMapping": {"google.subject": "assertion.sub"}}`,
+		`not-json`,
+	} {
+		api.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, EvaluatePath, strings.NewReader(body)))
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	out := rec.Body.String()
+
+	for _, expected := range []string{
+		`wif_http_requests_total{handler="/api/v1/evaluate",method="POST",code="200"} 2`,
+		`wif_http_requests_total{handler="/api/v1/evaluate",method="POST",code="422"} 1`,
+		`wif_http_requests_total{handler="/api/v1/evaluate",method="POST",code="400"} 2`,
+		`wif_evaluations_total{provider="oidc",outcome="accepted"} 1`,
+		`wif_evaluations_total{provider="oidc",outcome="rejected"} 1`,
+		`wif_evaluations_total{provider="oidc",outcome="error"} 1`,
+		`wif_evaluations_total{provider="unknown",outcome="error"} 1`,
+		`wif_evaluation_duration_seconds_bucket{provider="oidc",le="+Inf"} 3`,
+		`wif_evaluation_duration_seconds_count{provider="oidc"} 3`,
+		"# TYPE wif_evaluation_duration_seconds histogram",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("GET %s = %s, expected %s", MetricsPath, out, expected)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	health := NewHealth()
+	handler := health.Handler()
+
+	get := func(path string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get(HealthzPath); code != http.StatusOK {
+		t.Fatalf("GET %s = %d, expected %d", HealthzPath, code, http.StatusOK)
+	}
+
+	if code := get(ReadyzPath); code != http.StatusServiceUnavailable {
+		t.Fatalf("GET %s = %d before SetReady(), expected %d", ReadyzPath, code, http.StatusServiceUnavailable)
+	}
+
+	health.SetReady(true)
+	if code := get(ReadyzPath); code != http.StatusOK {
+		t.Fatalf("GET %s = %d, expected %d", ReadyzPath, code, http.StatusOK)
+	}
+
+	health.AddCheck("store", func() error { return errors.New("unavailable") })
+	if code := get(ReadyzPath); code != http.StatusServiceUnavailable {
+		t.Fatalf("GET %s = %d with a failing check, expected %d", ReadyzPath, code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	pb "github.com/loicsikidi/wif-go/pkg/generated/protobuf"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// shareSweepInterval is the interval between two sweeps of the expired shares
+const shareSweepInterval = time.Hour
+
+// Server serves the playground, the HTTP API and the gRPC service
+type Server struct {
+	config *Config
+	health *Health
+	// shares is the store of the shares, nil when shares are disabled
+	shares *FileStore
+	http   *http.Server
+	grpc   *grpc.Server
+	// audit is the file of the audit log, closed on shutdown
+	audit io.Closer
+}
+
+// New returns a server, static holds the playground (nil to serve no playground)
+func New(c *Config, static fs.FS) (*Server, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	metrics := NewMetrics()
+	s := &Server{config: c, health: NewHealth()}
+
+	mux := http.NewServeMux()
+	mux.Handle(HealthzPath, s.health.Handler())
+	mux.Handle(ReadyzPath, s.health.Handler())
+	mux.Handle(MetricsPath, metrics)
+
+	if static != nil {
+		mux.Handle("/", metrics.Instrument("static", http.FileServer(http.FS(static))))
+	}
+
+	if c.EnableAPI {
+		var shares ShareStore
+		if c.SharesDir != "" {
+			store, err := NewFileStore(c.SharesDir)
+
+			if err != nil {
+				return nil, err
+			}
+			store.MaxCount = c.MaxShares
+			store.MaxSize = c.MaxSharesSize
+			s.health.AddCheck("shares", store.Check)
+			s.shares = store
+			shares = store
+		}
+
+		opts := []grpc.ServerOption{grpc.UnaryInterceptor(metrics.UnaryInterceptor())}
+		if c.GRPCAddr != "" && c.TLS() {
+			creds, err := credentials.NewServerTLSFromFile(c.TLSCertFile, c.TLSKeyFile)
+
+			if err != nil {
+				return nil, fmt.Errorf("error loading TLS certificate: %w", err)
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+
+		// the audit log is opened last so that its file isn't leaked when the configuration above fails
+		auditor, err := s.auditor()
+
+		if err != nil {
+			return nil, err
+		}
+
+		mux.Handle("/api/", NewAPI(shares, metrics, auditor))
+
+		if c.GRPCAddr != "" {
+			s.grpc = grpc.NewServer(opts...)
+			pb.RegisterWifServiceServer(s.grpc, NewWifService(metrics, auditor))
+		}
+	}
+
+	s.http = &http.Server{
+		Addr:              c.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: c.ReadTimeout,
+		ReadTimeout:       c.ReadTimeout,
+		WriteTimeout:      c.WriteTimeout,
+	}
+	return s, nil
+}
+
+// auditor returns the auditor of the evaluations, nil when the audit log is disabled
+func (s *Server) auditor() (Observer, error) {
+	c := s.config
+	if c.AuditLog == "" {
+		return nil, nil
+	}
+
+	policy, err := c.Audit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.AuditLog == "-" {
+		return NewAuditor(os.Stdout, policy), nil
+	}
+
+	f, err := os.OpenFile(c.AuditLog, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+
+	if err != nil {
+		return nil, fmt.Errorf("error opening the audit log: %w", err)
+	}
+	s.audit = f
+	return NewAuditor(f, policy), nil
+}
+
+// Run listens on the configured addresses and serves until the context is canceled
+func (s *Server) Run(ctx context.Context) error {
+	httpListener, err := net.Listen("tcp", s.config.Addr)
+
+	if err != nil {
+		return err
+	}
+
+	var grpcListener net.Listener
+	if s.grpc != nil {
+		grpcListener, err = net.Listen("tcp", s.config.GRPCAddr)
+
+		if err != nil {
+			httpListener.Close()
+			return err
+		}
+	}
+	return s.Serve(ctx, httpListener, grpcListener)
+}
+
+// Serve serves on the given listeners until the context is canceled,
+// then in-flight requests are given Config.ShutdownTimeout to complete.
+// grpcListener is ignored when the gRPC server is disabled.
+func (s *Server) Serve(ctx context.Context, httpListener, grpcListener net.Listener) error {
+	errs := make(chan error, 2)
+
+	go func() {
+		log.Printf("server is listening on %s", httpListener.Addr())
+
+		var err error
+		if s.config.TLS() {
+			err = s.http.ServeTLS(httpListener, s.config.TLSCertFile, s.config.TLSKeyFile)
+		} else {
+			err = s.http.Serve(httpListener)
+		}
+
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+
+	if s.grpc != nil {
+		go func() {
+			log.Printf("gRPC server is listening on %s", grpcListener.Addr())
+			if err := s.grpc.Serve(grpcListener); err != nil {
+				errs <- fmt.Errorf("gRPC server: %w", err)
+			}
+		}()
+	}
+
+	if s.shares != nil {
+		go s.sweepShares(ctx)
+	}
+
+	s.health.SetReady(true)
+
+	var err error
+	select {
+	case <-ctx.Done():
+		log.Printf("shutting down the server")
+	case err = <-errs:
+	}
+
+	s.health.SetReady(false)
+	if shutdownErr := s.shutdown(); err == nil {
+		err = shutdownErr
+	}
+	return err
+}
+
+// sweepShares deletes the expired shares periodically until the context is canceled
+func (s *Server) sweepShares(ctx context.Context) {
+	ticker := time.NewTicker(shareSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.shares.Sweep(time.Now()); err != nil {
+			log.Printf("error sweeping expired shares: %s", err)
+		} else if n > 0 {
+			log.Printf("%d expired shares deleted", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// shutdown stops the servers gracefully, within Config.ShutdownTimeout
+func (s *Server) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	// the audit log is closed once the in-flight requests completed
+	if s.audit != nil {
+		defer s.audit.Close()
+	}
+
+	if s.grpc != nil {
+		stopped := make(chan struct{})
+		go func() {
+			s.grpc.GracefulStop()
+			close(stopped)
+		}()
+		defer func() {
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				s.grpc.Stop()
+			}
+		}()
+	}
+
+	if err := s.http.Shutdown(ctx); err != nil {
+		return fmt.Errorf("error shutting down the HTTP server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func newConfig(t *testing.T) *Config {
+	t.Helper()
+
+	c, err := LoadConfig([]string{"--shares-dir", t.TempDir()}, func(string) (string, bool) { return "", false }, io.Discard)
+
+	if err != nil {
+		t.Fatalf("LoadConfig() = %s, expected no error", err)
+	}
+	return c
+}
+
+// start serves on random ports and returns the base URL of the HTTP server and a function stopping the server
+func start(t *testing.T, s *Server) (string, func() error) {
+	t.Helper()
+
+	httpListener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("net.Listen() = %s, expected no error", err)
+	}
+
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("net.Listen() = %s, expected no error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, httpListener, grpcListener) }()
+
+	scheme := "http"
+	if s.config.TLS() {
+		scheme = "https"
+	}
+	return scheme + "://" + httpListener.Addr().String(), func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+
+	var err error
+	for i := 0; i < 50; i++ {
+		var resp *http.Response
+		if resp, err = client.Get(url); err == nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return resp.StatusCode, string(body)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("GET %s = %s, expected no error", url, err)
+	return 0, ""
+}
+
+func TestServer(t *testing.T) {
+	static := fstest.MapFS{"index.html": {Data: []byte("playground")}}
+	s, err := New(newConfig(t), static)
+
+	if err != nil {
+		t.Fatalf("New() = %s, expected no error", err)
+	}
+
+	url, stop := start(t, s)
+
+	for _, tc := range []struct{ path, expected string }{
+		{path: "/", expected: "playground"},
+		{path: HealthzPath, expected: "ok"},
+		{path: ReadyzPath, expected: "ok"},
+		{path: MetricsPath, expected: `wif_http_requests_total{handler="static",method="GET",code="200"} 1`},
+	} {
+		if code, body := get(t, http.DefaultClient, url+tc.path); code != http.StatusOK || !strings.Contains(body, tc.expected) {
+			t.Fatalf("GET %s = %d %s, expected %s", tc.path, code, body, tc.expected)
+		}
+	}
+
+	if code, _ := get(t, http.DefaultClient, url+SharesPath+"/"+strings.Repeat("a", shareIDLength)); code != http.StatusNotFound {
+		t.Fatalf("GET %s = %d, expected %d", SharesPath, code, http.StatusNotFound)
+	}
+
+	if err := stop(); err != nil {
+		t.Fatalf("Serve() = %s, expected no error", err)
+	}
+
+	if err := s.health.Ready(); err == nil {
+		t.Fatalf("Ready() -> expect exception after shutdown")
+	}
+}
+
+func TestServerWithoutAPI(t *testing.T) {
+	c := newConfig(t)
+	c.EnableAPI = false
+	s, err := New(c, nil)
+
+	if err != nil {
+		t.Fatalf("New() = %s, expected no error", err)
+	}
+
+	url, stop := start(t, s)
+	defer stop()
+
+	if code, _ := get(t, http.DefaultClient, url+EvaluatePath); code != http.StatusNotFound {
+		t.Fatalf("GET %s = %d, expected %d", EvaluatePath, code, http.StatusNotFound)
+	}
+
+	if s.grpc != nil {
+		t.Fatalf("New() = gRPC server, expected none")
+	}
+}
+
+func TestServerTLS(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	keyDer, _ := x509.MarshalECPrivateKey(key)
+
+	dir := t.TempDir()
+	c := newConfig(t)
+	c.TLSCertFile, c.TLSKeyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	_ = os.WriteFile(c.TLSCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	_ = os.WriteFile(c.TLSKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600)
+
+	s, err := New(c, nil)
+
+	if err != nil {
+		t.Fatalf("New() = %s, expected no error", err)
+	}
+
+	url, stop := start(t, s)
+	defer stop()
+
+	cert, _ := x509.ParseCertificate(der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}}
+
+	if code, _ := get(t, client, url+HealthzPath); code != http.StatusOK {
+		t.Fatalf("GET %s = %d, expected %d", HealthzPath, code, http.StatusOK)
+	}
+
+	// the audit log isn't opened when the TLS certificate can't be loaded
+	c.TLSCertFile = filepath.Join(dir, "missing.pem")
+	c.AuditLog = filepath.Join(dir, "audit.log")
+	if _, err := New(c, nil); err == nil {
+		t.Fatalf("New() -> expect exception")
+	}
+
+	if _, err := os.Stat(c.AuditLog); !os.IsNotExist(err) {
+		t.Fatalf("Stat(%s) = %v, expected the audit log not to be opened", c.AuditLog, err)
+	}
+}
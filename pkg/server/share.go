@@ -0,0 +1,360 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+// MaximumShareTTL is the maximum lifetime of a share.
+const MaximumShareTTL = 90 * 24 * time.Hour
+
+// ErrShareNotFound means that a share doesn't exist or has expired.
+var ErrShareNotFound = errors.New("share not found")
+
+// ErrShareQuotaExceeded means that storing a share would exceed the quota of the store.
+var ErrShareQuotaExceeded = errors.New("share quota exceeded")
+
+// shareIDLength is the length of a share ID (ie. 128 bits encoded in base64url)
+const shareIDLength = 22
+
+var shareID = regexp.MustCompile(fmt.Sprintf("^[A-Za-z0-9_-]{%d}$", shareIDLength))
+
+// ProviderConfig is the configuration of the OIDC provider of a playground state (cf. the REST representation of a provider)
+type ProviderConfig struct {
+	IssuerURI        string   `json:"issuerUri,omitempty"`
+	AllowedAudiences []string `json:"allowedAudiences,omitempty"`
+	JWKSJSON         string   `json:"jwksJson,omitempty"`
+}
+
+// Scenario is a playground state, an evaluation with the settings of the playground
+type Scenario struct {
+	EvaluateRequest
+	// [Optional] ProviderConfig checks the token against the provider (issuer, audiences and signature).
+	ProviderConfig *ProviderConfig `json:"providerConfig,omitempty"`
+	// [Optional] Mode is either workload (default) or workforce.
+	Mode string `json:"mode,omitempty"`
+}
+
+// ShareRequest is a playground state to share
+type ShareRequest struct {
+	Scenario
+	// [Optional] TTL is the lifetime of the share (eg. 72h), it never expires by default.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// Share is a stored playground state
+type Share struct {
+	Scenario
+	// ID of the share, derived from its content.
+	ID string `json:"id"`
+	// CreatedAt is the time the share was first stored.
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt is the time the share expires, nil when it never expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Expired tells whether the share has expired at a given time
+func (s *Share) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
+
+// ShareStore persists shares
+type ShareStore interface {
+	// Get returns a share, ErrShareNotFound when it doesn't exist.
+	Get(id string) (*Share, error)
+	// Put stores a share, replacing the one with the same ID.
+	Put(s *Share) error
+	// Delete removes a share.
+	Delete(id string) error
+}
+
+// NewShare returns the share of a playground state, its ID is derived from the state
+// so that sharing the same scenario twice yields the same link
+func NewShare(req *ShareRequest, now time.Time) (*Share, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	backend, err := req.Backend()
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Share{Scenario: req.Scenario, CreatedAt: now.UTC()}
+	s.Provider = provider.BackendName(backend)
+
+	// the defaults are omitted, so that the IDs of the scenarios without these settings don't change
+	switch s.Mode {
+	case "", "workload":
+		s.Mode = ""
+	case "workforce":
+	default:
+		return nil, fmt.Errorf("unknown mode %q. Only 'workload' and 'workforce' are accepted", s.Mode)
+	}
+
+	if c := s.ProviderConfig; c != nil && c.IssuerURI == "" && len(c.AllowedAudiences) == 0 && c.JWKSJSON == "" {
+		s.ProviderConfig = nil
+	}
+
+	if req.TTL != "" {
+		ttl, err := time.ParseDuration(req.TTL)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl: %w", err)
+		}
+
+		if ttl <= 0 || ttl > MaximumShareTTL {
+			return nil, fmt.Errorf("the ttl must be positive and can't exceed %s", MaximumShareTTL)
+		}
+
+		expiresAt := s.CreatedAt.Add(ttl)
+		s.ExpiresAt = &expiresAt
+	}
+
+	// encoding/json sorts map keys, so the encoding is canonical
+	content, err := json.Marshal(&s.Scenario)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+	s.ID = base64.RawURLEncoding.EncodeToString(sum[:16])
+	return s, nil
+}
+
+// FileStore stores shares as JSON files in a directory
+type FileStore struct {
+	// Dir is the directory holding the shares.
+	Dir string
+	// MaxCount is the maximum number of shares, 0 for no limit.
+	MaxCount int
+	// MaxSize is the maximum total size of the shares in bytes, 0 for no limit.
+	MaxSize int64
+	// mu serializes the writes so that the quota can't be exceeded by concurrent shares
+	mu sync.Mutex
+}
+
+// NewFileStore returns a store of shares without quota, the directory is created if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating shares directory: %w", err)
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+func (f *FileStore) path(id string) (string, error) {
+	if !shareID.MatchString(id) {
+		return "", ErrShareNotFound
+	}
+	return filepath.Join(f.Dir, id+".json"), nil
+}
+
+// Get returns a share, ErrShareNotFound when it doesn't exist
+func (f *FileStore) Get(id string) (*Share, error) {
+	path, err := f.path(id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrShareNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	s := &Share{}
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("error decoding share '%s': %w", id, err)
+	}
+	return s, nil
+}
+
+// Put stores a share, the file is replaced atomically
+func (f *FileStore) Put(s *Share) error {
+	path, err := f.path(s.ID)
+
+	if err != nil {
+		return fmt.Errorf("invalid share ID '%s'", s.ID)
+	}
+
+	content, err := json.Marshal(s)
+
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.reserve(s.ID, int64(len(content))); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(f.Dir, ".share-*")
+
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// reserve checks that a share of a given size fits in the quota, expired shares are swept when it doesn't
+func (f *FileStore) reserve(id string, size int64) error {
+	if f.MaxCount <= 0 && f.MaxSize <= 0 {
+		return nil
+	}
+
+	fits := func() (bool, error) {
+		count, total, err := f.usage(id)
+
+		if err != nil {
+			return false, err
+		}
+		return (f.MaxCount <= 0 || count+1 <= f.MaxCount) && (f.MaxSize <= 0 || total+size <= f.MaxSize), nil
+	}
+
+	ok, err := fits()
+
+	if err != nil || ok {
+		return err
+	}
+
+	if _, err := f.sweep(time.Now()); err != nil {
+		return err
+	}
+
+	ok, err = fits()
+
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrShareQuotaExceeded
+	}
+	return nil
+}
+
+// usage returns the number of shares and their total size, the share being replaced (ie. id) excluded
+func (f *FileStore) usage(id string) (int, int64, error) {
+	entries, err := os.ReadDir(f.Dir)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count, total := 0, int64(0)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".json") || name == id+".json" {
+			continue
+		}
+
+		info, err := e.Info()
+
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return 0, 0, err
+		}
+		count++
+		total += info.Size()
+	}
+	return count, total, nil
+}
+
+// Sweep deletes the shares expired at a given time and returns how many were deleted
+func (f *FileStore) Sweep(now time.Time) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.sweep(now)
+}
+
+func (f *FileStore) sweep(now time.Time) (int, error) {
+	entries, err := os.ReadDir(f.Dir)
+
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if e.IsDir() || !ok {
+			continue
+		}
+
+		s, err := f.Get(id)
+
+		if err != nil {
+			// eg. a file which isn't a share
+			continue
+		}
+
+		if !s.Expired(now) {
+			continue
+		}
+
+		if err := f.Delete(id); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// Check returns an error when the directory of the shares isn't available
+func (f *FileStore) Check() error {
+	info, err := os.Stat(f.Dir)
+
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", f.Dir)
+	}
+	return nil
+}
+
+// Delete removes a share
+func (f *FileStore) Delete(id string) error {
+	path, err := f.path(id)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewShare(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	state := EvaluateRequest{
+		Payload:            `{"sub": "alice"}`,
+		AttributeMapping:   map[string]string{"google.subject": "assertion.sub", "attribute.org": "assertion.org"},
+		AttributeCondition: "attribute.org == 'octo-org'",
+	}
+
+	s, err := NewShare(&ShareRequest{Scenario: Scenario{EvaluateRequest: state}}, now)
+
+	if err != nil {
+		t.Fatalf("NewShare() = %s, expected no error", err)
+	}
+
+	if len(s.ID) != shareIDLength || s.Provider != "oidc" || s.ExpiresAt != nil {
+		t.Fatalf("NewShare() = %+v, expected an oidc share which never expires", s)
+	}
+
+	// the ID only depends on the content
+	explicit := state
+	explicit.Provider = "OIDC"
+	other, _ := NewShare(&ShareRequest{Scenario: Scenario{EvaluateRequest: explicit}, TTL: "24h"}, now.Add(time.Hour))
+
+	if other.ID != s.ID || !other.ExpiresAt.Equal(now.Add(25*time.Hour)) {
+		t.Fatalf("NewShare() = %+v, expected ID %s expiring in 24h", other, s.ID)
+	}
+
+	// the default settings of the playground don't change the ID
+	defaults := Scenario{EvaluateRequest: state, ProviderConfig: &ProviderConfig{}, Mode: "workload"}
+	if other, _ := NewShare(&ShareRequest{Scenario: defaults}, now); other.ID != s.ID || other.Mode != "" || other.ProviderConfig != nil {
+		t.Fatalf("NewShare() = %+v, expected ID %s without settings", other, s.ID)
+	}
+
+	changed := state
+	changed.AttributeCondition = "true"
+	for _, scenario := range []Scenario{
+		{EvaluateRequest: changed},
+		{EvaluateRequest: state, Mode: "workforce"},
+		{EvaluateRequest: state, ProviderConfig: &ProviderConfig{IssuerURI: "https://token.actions.githubusercontent.com"}},
+	} {
+		if other, _ := NewShare(&ShareRequest{Scenario: scenario}, now); other.ID == s.ID {
+			t.Fatalf("NewShare(%+v) = %s, expected a different ID", scenario, other.ID)
+		}
+	}
+
+	for _, req := range []*ShareRequest{
+		{Scenario: Scenario{EvaluateRequest: state}, TTL: "1d"},
+		{Scenario: Scenario{EvaluateRequest: state}, TTL: "-1h"},
+		{Scenario: Scenario{EvaluateRequest: state}, TTL: fmt.Sprintf("%dh", 91*24)},
+		{Scenario: Scenario{EvaluateRequest: state, Mode: "workspace"}},
+		{Scenario: Scenario{EvaluateRequest: EvaluateRequest{Provider: "x509"}}},
+		{Scenario: Scenario{EvaluateRequest: EvaluateRequest{Payload: strings.Repeat("a", MaximumPayloadLengthInBytes+1)}}},
+	} {
+		if _, err := NewShare(req, now); err == nil {
+			t.Fatalf("NewShare(%+v) -> expect exception", req)
+		}
+	}
+}
+
+func TestShares(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+
+	if err != nil {
+		t.Fatalf("NewFileStore() = %s, expected no error", err)
+	}
+
+	api := NewAPI(store, nil)
+	do := func(t *testing.T, method, path, body string, v any) int {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		if v != nil {
+			if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+				t.Fatalf("%s %s: error decoding response: %s", method, path, err)
+			}
+		}
+		return rec.Code
+	}
+
+	body := `{"payload": "{\"sub\": \"bob\"}", "attributeMapping": {"google.subject": "assertion.sub"}, "mode": "workforce", "providerConfig": {"issuerUri": "https://issuer.example.com", "allowedAudiences": ["wif"]}}`
+	settings := &Share{}
+	if code := do(t, http.MethodPost, SharesPath, body, settings); code != http.StatusCreated {
+		t.Fatalf("POST %s = %d (%+v), expected %d", SharesPath, code, settings, http.StatusCreated)
+	}
+
+	got := &Share{}
+	if code := do(t, http.MethodGet, SharesPath+"/"+settings.ID, "", got); code != http.StatusOK || got.Mode != "workforce" || got.ProviderConfig == nil || got.ProviderConfig.IssuerURI != "https://issuer.example.com" {
+		t.Fatalf("GET %s/%s = %d (%+v), expected the settings of the playground", SharesPath, settings.ID, code, got)
+	}
+
+	body = `{"payload": "{\"sub\": \"alice\"}", "attributeMapping": {"google.subject": "assertion.sub"}, "ttl": "1h"}`
+	created := &Share{}
+	if code := do(t, http.MethodPost, SharesPath, body, created); code != http.StatusCreated || created.ExpiresAt == nil {
+		t.Fatalf("POST %s = %d (%+v), expected %d", SharesPath, code, created, http.StatusCreated)
+	}
+
+	// sharing again without ttl makes the share permanent
+	body = `{"payload": "{\"sub\": \"alice\"}", "attributeMapping": {"google.subject": "assertion.sub"}}`
+	again := &Share{}
+	if code := do(t, http.MethodPost, SharesPath, body, again); code != http.StatusCreated || again.ID != created.ID || again.ExpiresAt != nil || !again.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("POST %s = %d (%+v), expected share %s without expiry", SharesPath, code, again, created.ID)
+	}
+
+	got = &Share{}
+	if code := do(t, http.MethodGet, SharesPath+"/"+created.ID, "", got); code != http.StatusOK || got.Payload != `{"sub": "alice"}` || got.AttributeMapping["google.subject"] != "assertion.sub" {
+		t.Fatalf("GET %s/%s = %d (%+v)", SharesPath, created.ID, code, got)
+	}
+
+	expiresAt := time.Now().Add(-time.Minute)
+	got.ExpiresAt = &expiresAt
+	if err := store.Put(got); err != nil {
+		t.Fatalf("Put() = %s, expected no error", err)
+	}
+
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		expected int
+	}{
+		// expired
+		{method: http.MethodGet, path: SharesPath + "/" + created.ID, expected: http.StatusNotFound},
+		{method: http.MethodGet, path: SharesPath + "/" + strings.Repeat("a", shareIDLength), expected: http.StatusNotFound},
+		{method: http.MethodGet, path: SharesPath + "/not-an-id", expected: http.StatusNotFound},
+		{method: http.MethodDelete, path: SharesPath + "/" + created.ID, expected: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: SharesPath, expected: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: SharesPath, body: `{"payload": "{}", "ttl": "forever"}`, expected: http.StatusBadRequest},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			resp := &ErrorResponse{}
+			if code := do(t, tc.method, tc.path, tc.body, resp); code != tc.expected || resp.Error == nil {
+				t.Fatalf("%s %s = %d (%+v), expected %d", tc.method, tc.path, code, resp.Error, tc.expected)
+			}
+		})
+	}
+
+	if _, err := store.Get(created.ID); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("Get() = %v, expected the expired share to be deleted", err)
+	}
+}
+
+func TestFileStoreQuota(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+
+	if err != nil {
+		t.Fatalf("NewFileStore() = %s, expected no error", err)
+	}
+	store.MaxCount = 2
+
+	now := time.Now()
+	share := func(sub string, ttl string) *Share {
+		t.Helper()
+
+		s, err := NewShare(&ShareRequest{Scenario: Scenario{EvaluateRequest: EvaluateRequest{Payload: fmt.Sprintf(`{"sub": %q}`, sub)}}, TTL: ttl}, now)
+
+		if err != nil {
+			t.Fatalf("NewShare() = %s, expected no error", err)
+		}
+		return s
+	}
+
+	expired, alice, bob := share("expired", "1h"), share("alice", ""), share("bob", "")
+	for _, s := range []*Share{expired, alice} {
+		if err := store.Put(s); err != nil {
+			t.Fatalf("Put(%s) = %s, expected no error", s.ID, err)
+		}
+	}
+
+	// replacing a share doesn't count
+	if err := store.Put(alice); err != nil {
+		t.Fatalf("Put(%s) = %s, expected no error", alice.ID, err)
+	}
+
+	if err := store.Put(bob); !errors.Is(err, ErrShareQuotaExceeded) {
+		t.Fatalf("Put(%s) = %v, expected %s", bob.ID, err, ErrShareQuotaExceeded)
+	}
+
+	// the expired shares are swept to make room
+	expiresAt := now.Add(-time.Minute)
+	expired.ExpiresAt = &expiresAt
+	store.MaxCount = 0
+	if err := store.Put(expired); err != nil {
+		t.Fatalf("Put(%s) = %s, expected no error", expired.ID, err)
+	}
+	store.MaxCount = 2
+
+	if err := store.Put(bob); err != nil {
+		t.Fatalf("Put(%s) = %s, expected no error", bob.ID, err)
+	}
+
+	if _, err := store.Get(expired.ID); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("Get(%s) = %v, expected the expired share to be swept", expired.ID, err)
+	}
+
+	store.MaxCount, store.MaxSize = 0, 10
+	if err := store.Put(share("carol", "")); !errors.Is(err, ErrShareQuotaExceeded) {
+		t.Fatalf("Put() = %v, expected %s", err, ErrShareQuotaExceeded)
+	}
+
+	api := NewAPI(store, nil)
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, SharesPath, strings.NewReader(`{"payload": "{\"sub\": \"dave\"}"}`)))
+	if rec.Code != http.StatusInsufficientStorage {
+		t.Fatalf("POST %s = %d, expected %d", SharesPath, rec.Code, http.StatusInsufficientStorage)
+	}
+}
+
+func TestFileStoreSweep(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+
+	if err != nil {
+		t.Fatalf("NewFileStore() = %s, expected no error", err)
+	}
+
+	now := time.Now()
+	for i, ttl := range []string{"1h", "2h", ""} {
+		s, _ := NewShare(&ShareRequest{Scenario: Scenario{EvaluateRequest: EvaluateRequest{Payload: fmt.Sprintf(`{"sub": "%d"}`, i)}}, TTL: ttl}, now)
+		if err := store.Put(s); err != nil {
+			t.Fatalf("Put(%s) = %s, expected no error", s.ID, err)
+		}
+	}
+
+	if n, err := store.Sweep(now.Add(90 * time.Minute)); err != nil || n != 1 {
+		t.Fatalf("Sweep() = %d, %v, expected 1 share deleted", n, err)
+	}
+
+	if n, err := store.Sweep(now.Add(24 * time.Hour)); err != nil || n != 1 {
+		t.Fatalf("Sweep() = %d, %v, expected 1 share deleted", n, err)
+	}
+
+	if count, _, _ := store.usage(""); count != 1 {
+		t.Fatalf("usage() = %d shares, expected the permanent share to be kept", count)
+	}
+}
@@ -0,0 +1,127 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/resource"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ResourceName returns the Terraform resource name derived from an ID (eg. ci-pool -> ci_pool)
+func ResourceName(id string) string {
+	name := strings.ReplaceAll(id, "-", "_")
+	// Terraform names can't start with a digit
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// Export generates the Terraform configuration of a pool and its providers,
+// which are validated first so that Terraform isn't given a configuration rejected by Google Cloud Platform.
+//
+// Strings are escaped so that Terraform evaluates exactly the same CEL expressions
+// (eg. '${' is written as '$${').
+func Export(pool *resource.Pool) ([]byte, error) {
+	if err := pool.Validate(); err != nil {
+		return nil, err
+	}
+
+	f := hclwrite.NewEmptyFile()
+	root := f.Body()
+	poolName := ResourceName(pool.ID)
+
+	body := root.AppendNewBlock("resource", []string{PoolResourceType, poolName}).Body()
+	if pool.ProjectNumber != "" {
+		body.SetAttributeValue("project", cty.StringVal(pool.ProjectNumber))
+	}
+	body.SetAttributeValue("workload_identity_pool_id", cty.StringVal(pool.ID))
+	setOptionalAttributes(body, pool.DisplayName, pool.Description, pool.Disabled)
+
+	for _, p := range pool.Providers {
+		root.AppendNewline()
+
+		if err := appendProvider(root, pool, p); err != nil {
+			return nil, err
+		}
+	}
+	return hclwrite.Format(f.Bytes()), nil
+}
+
+func setOptionalAttributes(body *hclwrite.Body, displayName, description string, disabled bool) {
+	if displayName != "" {
+		body.SetAttributeValue("display_name", cty.StringVal(displayName))
+	}
+	if description != "" {
+		body.SetAttributeValue("description", cty.StringVal(description))
+	}
+	if disabled {
+		body.SetAttributeValue("disabled", cty.True)
+	}
+}
+
+func appendProvider(root *hclwrite.Body, pool *resource.Pool, p *resource.Provider) error {
+	body := root.AppendNewBlock("resource", []string{ProviderResourceType, ResourceName(p.ID)}).Body()
+
+	if pool.ProjectNumber != "" {
+		body.SetAttributeValue("project", cty.StringVal(pool.ProjectNumber))
+	}
+	body.SetAttributeTraversal("workload_identity_pool_id", hcl.Traversal{
+		hcl.TraverseRoot{Name: PoolResourceType},
+		hcl.TraverseAttr{Name: ResourceName(pool.ID)},
+		hcl.TraverseAttr{Name: "workload_identity_pool_id"},
+	})
+	body.SetAttributeValue("workload_identity_pool_provider_id", cty.StringVal(p.ID))
+	setOptionalAttributes(body, p.DisplayName, p.Description, p.Disabled)
+
+	if len(p.AttributeMapping) > 0 {
+		mapping := make(map[string]cty.Value, len(p.AttributeMapping))
+		for k, v := range p.AttributeMapping {
+			mapping[k] = cty.StringVal(v)
+		}
+		body.SetAttributeValue("attribute_mapping", cty.MapVal(mapping))
+	}
+	if p.AttributeCondition != "" {
+		body.SetAttributeValue("attribute_condition", cty.StringVal(p.AttributeCondition))
+	}
+
+	switch p.Type {
+	case provider.OIDC:
+		if p.OIDC == nil {
+			return fmt.Errorf("provider %q: the OIDC configuration is missing", p.ID)
+		}
+		oidc := body.AppendNewBlock("oidc", nil).Body()
+		oidc.SetAttributeValue("issuer_uri", cty.StringVal(p.OIDC.IssuerURI))
+		if len(p.OIDC.AllowedAudiences) > 0 {
+			oidc.SetAttributeValue("allowed_audiences", stringList(p.OIDC.AllowedAudiences))
+		}
+		if p.OIDC.JWKSJSON != "" {
+			oidc.SetAttributeValue("jwks_json", cty.StringVal(p.OIDC.JWKSJSON))
+		}
+	case provider.AWS:
+		if p.AWS == nil {
+			return fmt.Errorf("provider %q: the AWS configuration is missing", p.ID)
+		}
+		body.AppendNewBlock("aws", nil).Body().SetAttributeValue("account_id", cty.StringVal(p.AWS.AccountID))
+	case provider.SAML:
+		if p.SAML == nil {
+			return fmt.Errorf("provider %q: the SAML configuration is missing", p.ID)
+		}
+		body.AppendNewBlock("saml", nil).Body().SetAttributeValue("idp_metadata_xml", cty.StringVal(p.SAML.IdPMetadataXML))
+	default:
+		return fmt.Errorf("provider %q: unknown provider backend %d", p.ID, p.Type)
+	}
+	return nil
+}
+
+func stringList(values []string) cty.Value {
+	list := make([]cty.Value, 0, len(values))
+	for _, v := range values {
+		list = append(list, cty.StringVal(v))
+	}
+	return cty.ListVal(list)
+}
@@ -0,0 +1,103 @@
+package terraform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/resource"
+)
+
+func TestExport(t *testing.T) {
+	pool := &resource.Pool{
+		ProjectNumber: "123456789012",
+		ID:            "ci-pool",
+		DisplayName:   "CI pool",
+		Providers: []*resource.Provider{
+			{
+				ID:   "github",
+				Type: provider.OIDC,
+				AttributeMapping: map[string]string{
+					compiler.GoogleSubject: "assertion.sub",
+					"attribute.ref":        `"ref:${assertion.ref}" + '%{literal}'`,
+				},
+				AttributeCondition: "assertion.repository_owner == \"octo-org\" &&\n  assertion.ref.startsWith('refs/heads/')",
+				OIDC:               &resource.OIDC{IssuerURI: "https://token.actions.githubusercontent.com", AllowedAudiences: []string{"wif", "sts"}},
+			},
+			{
+				ID:       "aws-prod",
+				Disabled: true,
+				Type:     provider.AWS,
+				AWS:      &resource.AWS{AccountID: "123456789012"},
+			},
+		},
+	}
+
+	out, err := Export(pool)
+
+	if err != nil {
+		t.Fatalf("Export() = %s, expected no error", err)
+	}
+
+	for _, expected := range []string{
+		`resource "google_iam_workload_identity_pool" "ci_pool" {`,
+		`resource "google_iam_workload_identity_pool_provider" "aws_prod" {`,
+		`google_iam_workload_identity_pool.ci_pool.workload_identity_pool_id`,
+		`"\"ref:$${assertion.ref}\" + '%%{literal}'"`,
+	} {
+		if !strings.Contains(string(out), expected) {
+			t.Fatalf("Export() = %s, expected to contain %s", out, expected)
+		}
+	}
+
+	// what is exported must be imported without any change
+	providers, err := Parse(out, "main.tf")
+
+	if err != nil {
+		t.Fatalf("Parse(%s) = %s, expected no error", out, err)
+	}
+	if len(providers) != len(pool.Providers) {
+		t.Fatalf("Parse(%s) = %d providers, expected %d", out, len(providers), len(pool.Providers))
+	}
+
+	for i, p := range providers {
+		if p.PoolID != pool.ID {
+			t.Fatalf("Parse(%s) = %s pool ID, expected %s", out, p.PoolID, pool.ID)
+		}
+		if !reflect.DeepEqual(p.Resource, pool.Providers[i]) {
+			t.Fatalf("Parse(%s) = %+v, expected %+v", out, p.Resource, pool.Providers[i])
+		}
+	}
+}
+
+func TestExportInvalidPool(t *testing.T) {
+	github := &resource.Provider{
+		ID:               "github",
+		Type:             provider.OIDC,
+		AttributeMapping: map[string]string{compiler.GoogleSubject: "assertion.sub"},
+		OIDC:             &resource.OIDC{IssuerURI: "https://token.actions.githubusercontent.com"},
+	}
+
+	for _, pool := range []*resource.Pool{
+		// the OIDC configuration is missing
+		{ProjectNumber: "123456789012", ID: "ci-pool", Providers: []*resource.Provider{{ID: "github", Type: provider.OIDC}}},
+		// the project number is missing
+		{ID: "ci-pool", Providers: []*resource.Provider{github}},
+		// the display name is too long
+		{ProjectNumber: "123456789012", ID: "ci-pool", DisplayName: strings.Repeat("a", resource.MaximumDisplayNameLength+1)},
+	} {
+		if _, err := Export(pool); err == nil {
+			t.Fatalf("Export(%v) -> expect exception", pool)
+		}
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	for id, expected := range map[string]string{"ci-pool": "ci_pool", "github": "github", "1-pool": "_1_pool"} {
+		if got := ResourceName(id); got != expected {
+			t.Fatalf("ResourceName(%s) = %s, expected %s", id, got, expected)
+		}
+	}
+}
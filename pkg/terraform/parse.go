@@ -0,0 +1,309 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/resource"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Terraform resource types handled by the package.
+//
+// (See more at https://registry.terraform.io/providers/hashicorp/google/latest/docs/resources/iam_workload_identity_pool_provider)
+const (
+	PoolResourceType     = "google_iam_workload_identity_pool"
+	ProviderResourceType = "google_iam_workload_identity_pool_provider"
+)
+
+// Provider is a provider resource found in a Terraform configuration
+type Provider struct {
+	// Address is the Terraform address of the resource (eg. google_iam_workload_identity_pool_provider.github).
+	Address string
+	// Filename is the file declaring the resource.
+	Filename string
+	// Line is the line where the resource is declared.
+	Line int
+	// PoolID is the ID of the pool owning the provider (empty if it can't be statically evaluated).
+	PoolID string
+	// Resource holds the provider configuration.
+	// Attributes which can't be statically evaluated (eg. references to variables) are left empty.
+	Resource *resource.Provider
+	// Input is the compiler input derived from the provider (Payload is left empty).
+	Input *compiler.Input
+}
+
+// ParseDir imports providers declared in every '.tf' file of a directory
+func ParseDir(dir string) ([]*Provider, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return ParseFiles(files...)
+}
+
+// ParseFiles imports providers declared in Terraform files.
+// Pools referenced by providers are resolved across all the files.
+func ParseFiles(filenames ...string) ([]*Provider, error) {
+	bodies := make([]*hclsyntax.Body, 0, len(filenames))
+
+	for _, filename := range filenames {
+		src, err := os.ReadFile(filename)
+
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := parseBody(src, filename)
+
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, body)
+	}
+	return parseBodies(bodies)
+}
+
+// Parse imports providers declared in a Terraform configuration
+func Parse(src []byte, filename string) ([]*Provider, error) {
+	body, err := parseBody(src, filename)
+
+	if err != nil {
+		return nil, err
+	}
+	return parseBodies([]*hclsyntax.Body{body})
+}
+
+func parseBody(src []byte, filename string) (*hclsyntax.Body, error) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("error parsing Terraform configuration: %w", diags)
+	}
+	return file.Body.(*hclsyntax.Body), nil
+}
+
+// resourceBlocks returns the resource blocks of a given type
+func resourceBlocks(body *hclsyntax.Body, resourceType string) []*hclsyntax.Block {
+	var blocks []*hclsyntax.Block
+	for _, block := range body.Blocks {
+		if block.Type == "resource" && len(block.Labels) == 2 && block.Labels[0] == resourceType {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+func parseBodies(bodies []*hclsyntax.Body) ([]*Provider, error) {
+	// pool IDs indexed by resource name, used to resolve references
+	pools := map[string]string{}
+
+	for _, body := range bodies {
+		for _, block := range resourceBlocks(body, PoolResourceType) {
+			if id, ok := stringAttr(block.Body, "workload_identity_pool_id"); ok {
+				pools[block.Labels[1]] = id
+			}
+		}
+	}
+
+	var providers []*Provider
+	for _, body := range bodies {
+		for _, block := range resourceBlocks(body, ProviderResourceType) {
+			p, err := parseProvider(block, pools)
+
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, p)
+		}
+	}
+	return providers, nil
+}
+
+func parseProvider(block *hclsyntax.Block, pools map[string]string) (*Provider, error) {
+	address := fmt.Sprintf("%s.%s", block.Labels[0], block.Labels[1])
+	body := block.Body
+
+	p := &Provider{
+		Address:  address,
+		Filename: block.DefRange().Filename,
+		Line:     block.DefRange().Start.Line,
+		PoolID:   poolID(body, pools),
+		Resource: &resource.Provider{},
+	}
+
+	r := p.Resource
+	r.ID, _ = stringAttr(body, "workload_identity_pool_provider_id")
+	r.DisplayName, _ = stringAttr(body, "display_name")
+	r.Description, _ = stringAttr(body, "description")
+	r.Disabled, _ = boolAttr(body, "disabled")
+
+	if attr, ok := body.Attributes["attribute_mapping"]; ok {
+		mapping, err := stringMap(attr)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", address, err)
+		}
+		r.AttributeMapping = mapping
+	}
+
+	if attr, ok := body.Attributes["attribute_condition"]; ok {
+		val, err := staticValue(attr)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", address, err)
+		}
+		if val.Type() != cty.String {
+			return nil, fmt.Errorf("%s: 'attribute_condition' must be a string", address)
+		}
+		r.AttributeCondition = val.AsString()
+	}
+
+	for _, nested := range body.Blocks {
+		switch nested.Type {
+		case "oidc":
+			r.Type = provider.OIDC
+			r.OIDC = &resource.OIDC{}
+			r.OIDC.IssuerURI, _ = stringAttr(nested.Body, "issuer_uri")
+			r.OIDC.JWKSJSON, _ = stringAttr(nested.Body, "jwks_json")
+			r.OIDC.AllowedAudiences = stringListAttr(nested.Body, "allowed_audiences")
+		case "aws":
+			r.Type = provider.AWS
+			r.AWS = &resource.AWS{}
+			r.AWS.AccountID, _ = stringAttr(nested.Body, "account_id")
+		case "saml":
+			r.Type = provider.SAML
+			r.SAML = &resource.SAML{}
+			r.SAML.IdPMetadataXML, _ = stringAttr(nested.Body, "idp_metadata_xml")
+		}
+	}
+
+	if r.OIDC == nil && r.AWS == nil && r.SAML == nil {
+		return nil, fmt.Errorf("%s: an 'oidc', 'aws' or 'saml' block is required", address)
+	}
+
+	p.Input = r.Input("")
+	return p, nil
+}
+
+// poolID resolves the pool ID of a provider, either from a literal or from
+// a reference to a pool resource (eg. google_iam_workload_identity_pool.main.workload_identity_pool_id)
+func poolID(body *hclsyntax.Body, pools map[string]string) string {
+	attr, ok := body.Attributes["workload_identity_pool_id"]
+
+	if !ok {
+		return ""
+	}
+
+	if id, ok := stringAttr(body, "workload_identity_pool_id"); ok {
+		return id
+	}
+
+	traversal, diags := hcl.AbsTraversalForExpr(attr.Expr)
+
+	if diags.HasErrors() || len(traversal) < 2 || traversal.RootName() != PoolResourceType {
+		return ""
+	}
+
+	if name, ok := traversal[1].(hcl.TraverseAttr); ok {
+		return pools[name.Name]
+	}
+	return ""
+}
+
+// staticValue evaluates an attribute without any variable nor function
+func staticValue(attr *hclsyntax.Attribute) (cty.Value, error) {
+	val, diags := attr.Expr.Value(nil)
+
+	if diags.HasErrors() {
+		return cty.NilVal, fmt.Errorf("'%s' can't be statically evaluated: %w", attr.Name, diags)
+	}
+	if !val.IsWhollyKnown() || val.IsNull() {
+		return cty.NilVal, fmt.Errorf("'%s' can't be statically evaluated", attr.Name)
+	}
+	return val, nil
+}
+
+func stringAttr(body *hclsyntax.Body, name string) (string, bool) {
+	attr, ok := body.Attributes[name]
+
+	if !ok {
+		return "", false
+	}
+
+	val, err := staticValue(attr)
+
+	if err != nil || val.Type() != cty.String {
+		return "", false
+	}
+	return val.AsString(), true
+}
+
+func boolAttr(body *hclsyntax.Body, name string) (bool, bool) {
+	attr, ok := body.Attributes[name]
+
+	if !ok {
+		return false, false
+	}
+
+	val, err := staticValue(attr)
+
+	if err != nil || val.Type() != cty.Bool {
+		return false, false
+	}
+	return val.True(), true
+}
+
+func stringListAttr(body *hclsyntax.Body, name string) []string {
+	attr, ok := body.Attributes[name]
+
+	if !ok {
+		return nil
+	}
+
+	val, err := staticValue(attr)
+
+	if err != nil || !(val.Type().IsTupleType() || val.Type().IsListType()) {
+		return nil
+	}
+
+	var list []string
+	for it := val.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.Type() == cty.String {
+			list = append(list, v.AsString())
+		}
+	}
+	return list
+}
+
+func stringMap(attr *hclsyntax.Attribute) (map[string]string, error) {
+	val, err := staticValue(attr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !(val.Type().IsObjectType() || val.Type().IsMapType()) {
+		return nil, fmt.Errorf("'%s' must be a map", attr.Name)
+	}
+
+	m := map[string]string{}
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		if v.Type() != cty.String {
+			return nil, fmt.Errorf("the value of '%s' in '%s' must be a string", k.AsString(), attr.Name)
+		}
+		m[k.AsString()] = v.AsString()
+	}
+	return m, nil
+}
@@ -0,0 +1,143 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+)
+
+const config = `
+resource "google_iam_workload_identity_pool" "main" {
+  workload_identity_pool_id = "ci-pool"
+  display_name              = "CI pool"
+}
+
+resource "google_iam_workload_identity_pool_provider" "github" {
+  workload_identity_pool_id          = google_iam_workload_identity_pool.main.workload_identity_pool_id
+  workload_identity_pool_provider_id = "github"
+  attribute_mapping = {
+    "google.subject"       = "assertion.sub"
+    "attribute.repository" = "assertion.repository"
+    "attribute.ref"        = "\"ref:$${assertion.ref}\""
+  }
+  attribute_condition = <<-EOT
+    assertion.repository_owner == "octo-org" &&
+    assertion.ref.startsWith("refs/heads/")
+  EOT
+  oidc {
+    issuer_uri        = "https://token.actions.githubusercontent.com"
+    allowed_audiences = ["wif", var.audience]
+  }
+}
+
+resource "google_iam_workload_identity_pool_provider" "aws" {
+  workload_identity_pool_id          = "other-pool"
+  workload_identity_pool_provider_id = var.provider_id
+  disabled                           = true
+  aws {
+    account_id = "123456789012"
+  }
+}
+`
+
+func TestParse(t *testing.T) {
+	providers, err := Parse([]byte(config), "main.tf")
+
+	if err != nil {
+		t.Fatalf("Parse() = %s, expected no error", err)
+	}
+	if len(providers) != 2 {
+		t.Fatalf("Parse() = %d providers, expected 2", len(providers))
+	}
+
+	github := providers[0]
+	if github.Address != "google_iam_workload_identity_pool_provider.github" || github.PoolID != "ci-pool" || github.Line != 7 {
+		t.Fatalf("Parse() = %+v, unexpected provider metadata", github)
+	}
+
+	expectedMapping := map[string]string{
+		compiler.GoogleSubject: "assertion.sub",
+		"attribute.repository": "assertion.repository",
+		"attribute.ref":        `"ref:${assertion.ref}"`,
+	}
+	if !reflect.DeepEqual(github.Input.AttributeMapping, expectedMapping) {
+		t.Fatalf("Parse() = %v, expected %v", github.Input.AttributeMapping, expectedMapping)
+	}
+
+	expectedCondition := "assertion.repository_owner == \"octo-org\" &&\nassertion.ref.startsWith(\"refs/heads/\")\n"
+	if github.Input.AttributeCondition != expectedCondition {
+		t.Fatalf("Parse() = %q, expected %q", github.Input.AttributeCondition, expectedCondition)
+	}
+
+	if github.Resource.Type != provider.OIDC || github.Resource.OIDC.IssuerURI != "https://token.actions.githubusercontent.com" {
+		t.Fatalf("Parse() = %+v, unexpected OIDC configuration", github.Resource.OIDC)
+	}
+	if !reflect.DeepEqual(github.Resource.OIDC.AllowedAudiences, []string(nil)) {
+		t.Fatalf("Parse() = %v, expected audiences to be ignored", github.Resource.OIDC.AllowedAudiences)
+	}
+
+	aws := providers[1]
+	if aws.Resource.Type != provider.AWS || aws.Resource.ID != "" || !aws.Resource.Disabled || aws.PoolID != "other-pool" {
+		t.Fatalf("Parse() = %+v, unexpected AWS provider", aws.Resource)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		// invalid HCL
+		`resource "google_iam_workload_identity_pool_provider" "github" {`,
+		// condition referencing a variable
+		`resource "google_iam_workload_identity_pool_provider" "github" {
+  attribute_condition = var.condition
+  oidc {}
+}`,
+		// mapping value which isn't a string
+		`resource "google_iam_workload_identity_pool_provider" "github" {
+  attribute_mapping = { "google.subject" = 1 }
+  oidc {}
+}`,
+		// missing provider block
+		`resource "google_iam_workload_identity_pool_provider" "github" {
+  attribute_mapping = { "google.subject" = "assertion.sub" }
+}`,
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			if _, err := Parse([]byte(tc), "main.tf"); err == nil {
+				t.Fatalf("Parse(%s) -> expect exception", tc)
+			}
+		})
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir := t.TempDir()
+	pool := `resource "google_iam_workload_identity_pool" "main" { workload_identity_pool_id = "ci-pool" }`
+	providerConfig := `resource "google_iam_workload_identity_pool_provider" "github" {
+  workload_identity_pool_id = google_iam_workload_identity_pool.main.workload_identity_pool_id
+  oidc { issuer_uri = "https://token.actions.githubusercontent.com" }
+}`
+
+	if err := os.WriteFile(filepath.Join(dir, "pool.tf"), []byte(pool), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "provider.tf"), []byte(providerConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	providers, err := ParseDir(dir)
+
+	if err != nil {
+		t.Fatalf("ParseDir() = %s, expected no error", err)
+	}
+	if len(providers) != 1 || providers[0].PoolID != "ci-pool" {
+		t.Fatalf("ParseDir() = %v, expected the pool reference to be resolved across files", providers)
+	}
+}
@@ -0,0 +1,319 @@
+package testsuite
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats supported by Write.
+const (
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatTAP   = "tap"
+)
+
+// JSONReportVersion is the version of the JSON report schema.
+// It's bumped on every breaking change.
+const JSONReportVersion = 1
+
+// Write encodes reports in the given format
+func Write(w io.Writer, format string, reports ...*Report) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, reports...)
+	case FormatJUnit:
+		return WriteJUnit(w, reports...)
+	case FormatTAP:
+		return WriteTAP(w, reports...)
+	default:
+		return fmt.Errorf("unknown report format %q. Only '%s', '%s' and '%s' are accepted", format, FormatJSON, FormatJUnit, FormatTAP)
+	}
+}
+
+// failureMessage summarizes why a case failed
+func (r *Result) failureMessage() string {
+	return strings.Join(r.Failures, "; ")
+}
+
+// failureDetails describes why a case failed, including attribute diffs
+func (r *Result) failureDetails() string {
+	var sb strings.Builder
+	for _, failure := range r.Failures {
+		sb.WriteString(failure + "\n")
+	}
+	for _, d := range r.Diff {
+		expected, _ := json.Marshal(d.Expected)
+		actual, _ := json.Marshal(d.Actual)
+		fmt.Fprintf(&sb, "%s:\n  - expected: %s\n  + actual:   %s\n", d.Name, expected, actual)
+	}
+	return sb.String()
+}
+
+// failureType returns the error category, or 'assertion' when the compiler didn't fail
+func (r *Result) failureType() string {
+	if r.Err != nil {
+		return r.Category()
+	}
+	return "assertion"
+}
+
+// errored tells whether a case failed because of an unexpected error of the compiler (eg. an invalid attribute mapping)
+// rather than because of its outcome (eg. a rejected credential or mismatching attributes)
+func (r *Result) errored() bool {
+	return !r.Passed && r.Err != nil && r.Case.Expect.Error == "" && !errors.Is(r.Err, compiler.ErrAttrConditionFailed)
+}
+
+type jsonDiff struct {
+	Attribute string `json:"attribute" yaml:"attribute"`
+	Expected  any    `json:"expected" yaml:"expected"`
+	Actual    any    `json:"actual" yaml:"actual"`
+}
+
+type jsonCase struct {
+	Name            string         `json:"name"`
+	Status          string         `json:"status"`
+	DurationSeconds float64        `json:"durationSeconds"`
+	Error           string         `json:"error,omitempty"`
+	ErrorCategory   string         `json:"errorCategory,omitempty"`
+	Failures        []string       `json:"failures,omitempty"`
+	Attributes      map[string]any `json:"attributes,omitempty"`
+	Diff            []*jsonDiff    `json:"diff,omitempty"`
+}
+
+type jsonSuite struct {
+	Name            string      `json:"name"`
+	Passed          int         `json:"passed"`
+	Failed          int         `json:"failed"`
+	DurationSeconds float64     `json:"durationSeconds"`
+	Cases           []*jsonCase `json:"cases"`
+}
+
+type jsonReport struct {
+	Version         int          `json:"version"`
+	Passed          int          `json:"passed"`
+	Failed          int          `json:"failed"`
+	DurationSeconds float64      `json:"durationSeconds"`
+	Suites          []*jsonSuite `json:"suites"`
+}
+
+// WriteJSON encodes reports as a JSON document (cf. JSONReportVersion)
+func WriteJSON(w io.Writer, reports ...*Report) error {
+	out := &jsonReport{Version: JSONReportVersion, Suites: []*jsonSuite{}}
+
+	for _, report := range reports {
+		suite := &jsonSuite{
+			Name:            report.Suite.Name,
+			Passed:          report.Passed,
+			Failed:          report.Failed,
+			DurationSeconds: report.Duration.Seconds(),
+			Cases:           []*jsonCase{},
+		}
+
+		for _, r := range report.Results {
+			c := &jsonCase{
+				Name:            r.Case.Name,
+				Status:          "pass",
+				DurationSeconds: r.Duration.Seconds(),
+				Failures:        r.Failures,
+				Attributes:      r.Attributes,
+			}
+			if !r.Passed {
+				c.Status = "fail"
+			}
+			if r.Err != nil {
+				c.Error = r.Err.Error()
+				c.ErrorCategory = r.Category()
+			}
+			for _, d := range r.Diff {
+				c.Diff = append(c.Diff, &jsonDiff{Attribute: d.Name, Expected: d.Expected, Actual: d.Actual})
+			}
+			suite.Cases = append(suite.Cases, c)
+		}
+
+		out.Passed += report.Passed
+		out.Failed += report.Failed
+		out.DurationSeconds += suite.DurationSeconds
+		out.Suites = append(out.Suites, suite)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Time      string           `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	Tests      int               `xml:"tests,attr"`
+	Failures   int               `xml:"failures,attr"`
+	Errors     int               `xml:"errors,attr"`
+	Time       string            `xml:"time,attr"`
+	TestSuites []*junitTestSuite `xml:"testsuite"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.6f", d.Seconds())
+}
+
+// WriteJUnit encodes reports as a JUnit XML document.
+// Cases failing because of an unexpected compiler error (eg. an invalid configuration) are reported as errors, the other ones as failures.
+func WriteJUnit(w io.Writer, reports ...*Report) error {
+	out := &junitTestSuites{}
+	var total time.Duration
+
+	for _, report := range reports {
+		suite := &junitTestSuite{
+			Name:  report.Suite.Name,
+			Tests: len(report.Results),
+			Time:  junitTime(report.Duration),
+		}
+
+		for _, r := range report.Results {
+			tc := &junitTestCase{
+				Name:      r.Case.Name,
+				Classname: report.Suite.Name,
+				Time:      junitTime(r.Duration),
+			}
+			if !r.Passed {
+				failure := &junitFailure{
+					Message: r.failureMessage(),
+					Type:    r.failureType(),
+					Details: r.failureDetails(),
+				}
+
+				if r.errored() {
+					tc.Error = failure
+					suite.Errors++
+				} else {
+					tc.Failure = failure
+					suite.Failures++
+				}
+			}
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+
+		out.Tests += suite.Tests
+		out.Failures += suite.Failures
+		out.Errors += suite.Errors
+		total += report.Duration
+		out.TestSuites = append(out.TestSuites, suite)
+	}
+	out.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type tapDiagnostic struct {
+	Message    string      `yaml:"message"`
+	Severity   string      `yaml:"severity"`
+	Category   string      `yaml:"category"`
+	DurationMs float64     `yaml:"duration_ms"`
+	Diff       []*jsonDiff `yaml:"diff,omitempty"`
+}
+
+// tapEscaper escapes a TAP description, '#' would otherwise start a directive (eg. the default name "case #1")
+var tapEscaper = strings.NewReplacer(`\`, `\\`, "#", `\#`)
+
+// WriteTAP encodes reports using the version 13 of the Test Anything Protocol
+func WriteTAP(w io.Writer, reports ...*Report) error {
+	total := 0
+	for _, report := range reports {
+		total += len(report.Results)
+	}
+
+	if _, err := fmt.Fprintf(w, "TAP version 13\n1..%d\n", total); err != nil {
+		return err
+	}
+
+	i := 0
+	for _, report := range reports {
+		for _, r := range report.Results {
+			i++
+			status := "ok"
+			if !r.Passed {
+				status = "not ok"
+			}
+
+			description := tapEscaper.Replace(fmt.Sprintf("%s: %s", report.Suite.Name, r.Case.Name))
+			if _, err := fmt.Fprintf(w, "%s %d - %s\n", status, i, description); err != nil {
+				return err
+			}
+
+			if r.Passed {
+				continue
+			}
+
+			diag := &tapDiagnostic{
+				Message:    r.failureMessage(),
+				Severity:   "fail",
+				Category:   r.failureType(),
+				DurationMs: float64(r.Duration.Microseconds()) / 1000,
+			}
+			for _, d := range r.Diff {
+				diag.Diff = append(diag.Diff, &jsonDiff{Attribute: d.Name, Expected: d.Expected, Actual: d.Actual})
+			}
+
+			var block strings.Builder
+			enc := yaml.NewEncoder(&block)
+			enc.SetIndent(2)
+
+			if err := enc.Encode(diag); err != nil {
+				return err
+			}
+
+			var sb strings.Builder
+			sb.WriteString("  ---\n")
+			for _, line := range strings.Split(strings.TrimRight(block.String(), "\n"), "\n") {
+				sb.WriteString("  " + line + "\n")
+			}
+			sb.WriteString("  ...\n")
+
+			if _, err := io.WriteString(w, sb.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
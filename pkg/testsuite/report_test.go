@@ -0,0 +1,179 @@
+package testsuite
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func newReport(t *testing.T) *Report {
+	s, err := Parse([]byte(inlineSuite), ".")
+
+	if err != nil {
+		t.Fatalf("Parse() = %s, expected no error", err)
+	}
+	s.Name = "inline"
+
+	report, err := s.Run()
+
+	if err != nil {
+		t.Fatalf("Run() = %s, expected no error", err)
+	}
+	return report
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Write(&buf, FormatJSON, newReport(t)); err != nil {
+		t.Fatalf("Write() = %s, expected no error", err)
+	}
+
+	var out jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("json.Unmarshal(%s) = %s, expected no error", buf.String(), err)
+	}
+
+	if out.Version != JSONReportVersion || out.Passed != 3 || out.Failed != 2 || len(out.Suites) != 1 {
+		t.Fatalf("WriteJSON() = %s, unexpected summary", buf.String())
+	}
+
+	cases := out.Suites[0].Cases
+	if cases[1].Status != "pass" || cases[1].ErrorCategory != "condition_failed" {
+		t.Fatalf("WriteJSON() = %+v, expected a rejected case", cases[1])
+	}
+	if cases[2].ErrorCategory != "evaluation" {
+		t.Fatalf("WriteJSON() = %+v, expected an evaluation error", cases[2])
+	}
+	if cases[3].Status != "fail" || len(cases[3].Diff) != 2 || cases[3].Diff[1].Attribute != "google.subject" {
+		t.Fatalf("WriteJSON() = %+v, expected a diff", cases[3])
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Write(&buf, FormatJUnit, newReport(t)); err != nil {
+		t.Fatalf("Write() = %s, expected no error", err)
+	}
+
+	var out junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("xml.Unmarshal(%s) = %s, expected no error", buf.String(), err)
+	}
+
+	if out.Tests != 5 || out.Failures != 2 || len(out.TestSuites) != 1 {
+		t.Fatalf("WriteJUnit() = %s, unexpected summary", buf.String())
+	}
+
+	cases := out.TestSuites[0].TestCases
+	if cases[0].Failure != nil || cases[3].Failure == nil || cases[3].Failure.Type != "assertion" {
+		t.Fatalf("WriteJUnit() = %s, unexpected failures", buf.String())
+	}
+	if !strings.Contains(cases[3].Failure.Details, `- expected: "eve"`) {
+		t.Fatalf("WriteJUnit() = %s, expected the diff in failure details", cases[3].Failure.Details)
+	}
+
+	// a broken configuration is an error rather than a failure
+	s, err := Parse([]byte(brokenSuite), ".")
+
+	if err != nil {
+		t.Fatalf("Parse() = %s, expected no error", err)
+	}
+
+	report, err := s.Run()
+
+	if err != nil {
+		t.Fatalf("Run() = %s, expected no error", err)
+	}
+
+	buf.Reset()
+	if err := WriteJUnit(&buf, report); err != nil {
+		t.Fatalf("WriteJUnit() = %s, expected no error", err)
+	}
+
+	out = junitTestSuites{}
+	if err := xml.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("xml.Unmarshal(%s) = %s, expected no error", buf.String(), err)
+	}
+
+	cases = out.TestSuites[0].TestCases
+	if out.Errors != 2 || out.Failures != 0 || cases[0].Error == nil || cases[0].Failure != nil || cases[0].Error.Type != "compilation" {
+		t.Fatalf("WriteJUnit() = %s, expected errors", buf.String())
+	}
+}
+
+const brokenSuite = `
+provider:
+  attributeMapping:
+    google.subject: assertion.sub +
+cases:
+  - name: accepted
+    claims: {sub: alice}
+  - name: rejected
+    claims: {sub: bob}
+    expect:
+      accepted: false
+`
+
+func TestWriteTAP(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Write(&buf, FormatTAP, newReport(t)); err != nil {
+		t.Fatalf("Write() = %s, expected no error", err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"TAP version 13\n1..5\n",
+		"ok 1 - inline: admin is accepted\n",
+		"not ok 4 - inline: wrong expectations\n  ---\n",
+		"    - attribute: attribute.missing\n",
+		"not ok 5 - inline: wrong outcome\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("WriteTAP() = %s, expected to contain %q", out, expected)
+		}
+	}
+}
+
+func TestWriteTAPEscapesDescriptions(t *testing.T) {
+	s, err := Parse([]byte(unnamedSuite), ".")
+
+	if err != nil {
+		t.Fatalf("Parse() = %s, expected no error", err)
+	}
+	s.Name = `C:\suites`
+
+	report, err := s.Run()
+
+	if err != nil {
+		t.Fatalf("Run() = %s, expected no error", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTAP(&buf, report); err != nil {
+		t.Fatalf("WriteTAP() = %s, expected no error", err)
+	}
+
+	// the default case name would otherwise be read as a directive
+	if expected := `ok 1 - C:\\suites: case \#1` + "\n"; !strings.Contains(buf.String(), expected) {
+		t.Fatalf("WriteTAP() = %s, expected to contain %q", buf.String(), expected)
+	}
+}
+
+const unnamedSuite = `
+provider:
+  attributeMapping:
+    google.subject: assertion.sub
+cases:
+  - claims: {sub: alice}
+`
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "xunit"); err == nil {
+		t.Fatalf("Write() -> expect exception")
+	}
+}
@@ -0,0 +1,209 @@
+package testsuite
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/config"
+	"github.com/loicsikidi/wif-go/pkg/coverage"
+)
+
+// AttributeDiff is a difference between an expected and an actual attribute
+type AttributeDiff struct {
+	// Name of the attribute (eg. google.subject).
+	Name string
+	// Expected value (nil if the attribute isn't expected).
+	Expected any
+	// Actual value (nil if the attribute is missing).
+	Actual any
+}
+
+// Result is the outcome of a case
+type Result struct {
+	// Case that was run.
+	Case *Case
+	// Passed tells whether the outcome matches the expectations.
+	Passed bool
+	// Attributes are the attributes derived from the token.
+	Attributes map[string]any
+	// Err is the error returned by the compiler.
+	Err error
+	// Failures explains why the case failed.
+	Failures []string
+	// Diff lists the attributes which don't match the expectations.
+	Diff []*AttributeDiff
+	// Duration of the evaluation.
+	Duration time.Duration
+}
+
+// Category returns the category of the error returned by the compiler (cf. compiler.ErrorCategory)
+func (r *Result) Category() string {
+	return compiler.ErrorCategory(r.Err)
+}
+
+// Report is the outcome of a suite
+type Report struct {
+	// Suite that was run.
+	Suite *Suite
+	// Results of each case, in the suite order.
+	Results []*Result
+	// Passed is the number of successful cases.
+	Passed int
+	// Failed is the number of failed cases.
+	Failed int
+	// Duration of the suite.
+	Duration time.Duration
+}
+
+// Run evaluates every case of the suite.
+// An error is returned when the configuration under test can't be resolved.
+func (s *Suite) Run() (*Report, error) {
+	t, err := s.target()
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Suite: s}
+	start := time.Now()
+
+	for _, c := range s.Cases {
+		r := s.runCase(t, c)
+
+		if r.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		report.Results = append(report.Results, r)
+	}
+
+	report.Duration = time.Since(start)
+	return report, nil
+}
+
+// Coverage evaluates every case and reports which branches of the attribute condition are exercised.
+// Cases which can't be evaluated (eg. an invalid token) are counted as errors.
+func (s *Suite) Coverage() (*coverage.Report, error) {
+	t, err := s.target()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if t.AttributeCondition == "" {
+		return &coverage.Report{}, nil
+	}
+
+	tracker, err := coverage.New(t.AttributeCondition)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range s.Cases {
+		comp, err := s.compiler(t, c)
+
+		if err != nil {
+			tracker.Report().Errors++
+			continue
+		}
+		_ = tracker.Observe(comp)
+	}
+	return tracker.Report(), nil
+}
+
+// compiler returns the compiler evaluating a case against the configuration under test
+func (s *Suite) compiler(t *config.Provider, c *Case) (*compiler.Compiler, error) {
+	p, err := provider.New(t.Backend)
+
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.payload(c)
+
+	if err != nil {
+		return nil, &compiler.Error{Category: compiler.CategoryInvalidToken, Err: err}
+	}
+
+	return &compiler.Compiler{
+		Input: &compiler.Input{
+			Payload:            payload,
+			AttributeMapping:   t.AttributeMapping,
+			AttributeCondition: t.AttributeCondition,
+		},
+		Provider: p,
+	}, nil
+}
+
+func (s *Suite) runCase(t *config.Provider, c *Case) *Result {
+	r := &Result{Case: c}
+	start := time.Now()
+
+	if comp, err := s.compiler(t, c); err != nil {
+		r.Err = err
+	} else {
+		r.Attributes, r.Err = comp.Run()
+	}
+
+	r.Duration = time.Since(start)
+	r.check()
+	return r
+}
+
+// check compares the outcome with the expectations
+func (r *Result) check() {
+	expect := r.Case.Expect
+
+	if expect.Error != "" {
+		if r.Err == nil {
+			r.Failures = append(r.Failures, fmt.Sprintf("expected an error containing %q, got none", expect.Error))
+		} else if !strings.Contains(r.Err.Error(), expect.Error) {
+			r.Failures = append(r.Failures, fmt.Sprintf("expected an error containing %q, got %q", expect.Error, r.Err))
+		}
+		r.Passed = len(r.Failures) == 0
+		return
+	}
+
+	accepted := expect.Accepted == nil || *expect.Accepted
+	switch {
+	case accepted && r.Err != nil:
+		r.Failures = append(r.Failures, fmt.Sprintf("expected the credential to be accepted, got %q", r.Err))
+	case !accepted && r.Err == nil:
+		r.Failures = append(r.Failures, "expected the credential to be rejected by the attribute condition, got accepted")
+	case !accepted && !errors.Is(r.Err, compiler.ErrAttrConditionFailed):
+		r.Failures = append(r.Failures, fmt.Sprintf("expected the credential to be rejected by the attribute condition, got %q", r.Err))
+	}
+
+	if r.Err == nil {
+		r.Diff = diffAttributes(expect.Attributes, r.Attributes)
+		if len(r.Diff) > 0 {
+			r.Failures = append(r.Failures, fmt.Sprintf("%d attribute(s) don't match the expectations", len(r.Diff)))
+		}
+	}
+	r.Passed = len(r.Failures) == 0
+}
+
+// diffAttributes compares the expected attributes with the actual ones
+func diffAttributes(expected, actual map[string]any) []*AttributeDiff {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var diff []*AttributeDiff
+	for _, name := range names {
+		if !reflect.DeepEqual(expected[name], actual[name]) {
+			diff = append(diff, &AttributeDiff{Name: name, Expected: expected[name], Actual: actual[name]})
+		}
+	}
+	return diff
+}
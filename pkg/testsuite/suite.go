@@ -0,0 +1,174 @@
+package testsuite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/loicsikidi/wif-go/pkg/common/util"
+	"github.com/loicsikidi/wif-go/pkg/config"
+	"github.com/loicsikidi/wif-go/pkg/rest"
+	"github.com/loicsikidi/wif-go/pkg/token"
+	"gopkg.in/yaml.v3"
+)
+
+// Terraform references a provider declared in a Terraform configuration
+type Terraform struct {
+	// [Required] File is the path of the Terraform file (relative to the suite).
+	File string `yaml:"file"`
+	// [Optional] Resource is the name or the address of the provider resource (eg. github).
+	// It's required when the file declares several providers.
+	Resource string `yaml:"resource,omitempty"`
+}
+
+// Provider is the provider configuration under test.
+//
+// It's either defined inline using the REST representation (cf. 'gcloud iam workload-identity-pools providers describe')
+// or imported from a Terraform configuration.
+type Provider struct {
+	rest.Provider `yaml:",inline"`
+	// Terraform imports the provider from a Terraform configuration.
+	Terraform *Terraform `yaml:"terraform,omitempty"`
+}
+
+// Expect holds the expected outcome of a case
+type Expect struct {
+	// [Optional] Accepted tells whether the credential must be accepted (default) or rejected by the attribute condition.
+	Accepted *bool `yaml:"accepted,omitempty"`
+	// [Optional] Attributes are the expected derived attributes.
+	// Only the listed attributes are compared.
+	Attributes map[string]any `yaml:"attributes,omitempty"`
+	// [Optional] Error is a substring of the expected error.
+	Error string `yaml:"error,omitempty"`
+}
+
+// Case is a token and its expected outcome
+type Case struct {
+	// [Required] Name of the case.
+	Name string `yaml:"name"`
+	// Token is either a JWT or a JSON document holding the claims.
+	Token string `yaml:"token,omitempty"`
+	// TokenFile is the path of the token (relative to the suite).
+	TokenFile string `yaml:"tokenFile,omitempty"`
+	// Claims are the claims of the token written in YAML.
+	Claims map[string]any `yaml:"claims,omitempty"`
+	// Expect is the expected outcome.
+	Expect Expect `yaml:"expect"`
+}
+
+// Suite is a provider configuration and the cases it's tested against
+type Suite struct {
+	// Name of the suite (defaults to the file name).
+	Name string `yaml:"name,omitempty"`
+	// [Required] Provider under test.
+	Provider *Provider `yaml:"provider"`
+	// [Required] Cases run against the provider.
+	Cases []*Case `yaml:"cases"`
+
+	// dir is the directory used to resolve relative paths
+	dir string
+}
+
+// Load reads a test suite from a YAML file
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := Parse(data, filepath.Dir(path))
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if s.Name == "" {
+		s.Name = filepath.Base(path)
+	}
+	return s, nil
+}
+
+// Parse decodes a test suite, relative paths are resolved from dir
+func Parse(data []byte, dir string) (*Suite, error) {
+	s := &Suite{}
+
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error decoding test suite: %w", err)
+	}
+
+	s.dir = dir
+
+	if s.Provider == nil {
+		return nil, fmt.Errorf("a provider is required")
+	}
+	if len(s.Cases) == 0 {
+		return nil, fmt.Errorf("at least one case is required")
+	}
+
+	for i, c := range s.Cases {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("case #%d", i+1)
+		}
+
+		sources := 0
+		for _, set := range []bool{c.Token != "", c.TokenFile != "", c.Claims != nil} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return nil, fmt.Errorf("%s: exactly one of 'token', 'tokenFile' or 'claims' is required", c.Name)
+		}
+	}
+	return s, nil
+}
+
+// path resolves a path relative to the suite
+func (s *Suite) path(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(s.dir, p)
+}
+
+// Files returns the files referenced by the suite (ie. the Terraform configuration and the tokens)
+func (s *Suite) Files() []string {
+	var files []string
+	if s.Provider.Terraform != nil {
+		files = append(files, s.path(s.Provider.Terraform.File))
+	}
+	for _, c := range s.Cases {
+		if c.TokenFile != "" {
+			files = append(files, s.path(c.TokenFile))
+		}
+	}
+	return files
+}
+
+// target resolves the provider backend, attribute mapping and condition under test
+func (s *Suite) target() (*config.Provider, error) {
+	p := s.Provider
+
+	if p.Terraform == nil {
+		return config.FromREST(&p.Provider), nil
+	}
+	return config.FromTerraform(s.path(p.Terraform.File), p.Terraform.Resource)
+}
+
+// payload returns the JSON claims of a case
+func (s *Suite) payload(c *Case) (string, error) {
+	switch {
+	case c.Claims != nil:
+		return util.JSONEncode(c.Claims)
+	case c.TokenFile != "":
+		data, err := os.ReadFile(s.path(c.TokenFile))
+
+		if err != nil {
+			return "", err
+		}
+		return token.Payload(string(data))
+	default:
+		return token.Payload(c.Token)
+	}
+}
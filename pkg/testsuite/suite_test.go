@@ -0,0 +1,147 @@
+package testsuite
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const inlineSuite = `
+provider:
+  attributeMapping:
+    google.subject: assertion.sub
+    google.groups: assertion.groups
+  attributeCondition: "'admins' in google.groups"
+  oidc:
+    issuerUri: https://example.com
+cases:
+  - name: admin is accepted
+    token: '{"sub": "alice", "groups": ["admins"]}'
+    expect:
+      attributes:
+        google.subject: alice
+        google.groups: [admins]
+  - name: user is rejected
+    claims: {sub: bob, groups: [users]}
+    expect:
+      accepted: false
+  - name: missing groups
+    claims: {sub: carol}
+    expect:
+      error: no such key
+  - name: wrong expectations
+    claims: {sub: dave, groups: [admins]}
+    expect:
+      attributes:
+        google.subject: eve
+        attribute.missing: value
+  - name: wrong outcome
+    claims: {sub: frank, groups: [users]}
+`
+
+func TestRun(t *testing.T) {
+	s, err := Parse([]byte(inlineSuite), ".")
+
+	if err != nil {
+		t.Fatalf("Parse() = %s, expected no error", err)
+	}
+
+	report, err := s.Run()
+
+	if err != nil {
+		t.Fatalf("Run() = %s, expected no error", err)
+	}
+
+	expected := []struct {
+		passed bool
+		diff   int
+	}{
+		{passed: true},
+		{passed: true},
+		{passed: true},
+		{passed: false, diff: 2},
+		{passed: false},
+	}
+
+	if report.Passed != 3 || report.Failed != 2 {
+		t.Fatalf("Run() = %d passed, %d failed, expected 3 passed, 2 failed", report.Passed, report.Failed)
+	}
+
+	for i, r := range report.Results {
+		if r.Passed != expected[i].passed || len(r.Diff) != expected[i].diff {
+			t.Fatalf("Run() = %+v for case %q, expected %+v", r, r.Case.Name, expected[i])
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	s, err := Load("testdata/suite.yaml")
+
+	if err != nil {
+		t.Fatalf("Load() = %s, expected no error", err)
+	}
+
+	report, err := s.Run()
+
+	if err != nil {
+		t.Fatalf("Run() = %s, expected no error", err)
+	}
+	if report.Failed != 0 {
+		t.Fatalf("Run() = %d failed, expected none: %+v", report.Failed, report.Results)
+	}
+
+	expected := []string{filepath.Join("testdata", "main.tf"), filepath.Join("testdata", "main.json")}
+	if files := s.Files(); !reflect.DeepEqual(files, expected) {
+		t.Fatalf("Files() = %v, expected %v", files, expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		`cases: [{name: a, claims: {sub: a}}]`,
+		`provider: {attributeMapping: {google.subject: assertion.sub}}`,
+		`{provider: {attributeMapping: {google.subject: assertion.sub}}, cases: [{name: a}]}`,
+		`{provider: {attributeMapping: {google.subject: assertion.sub}}, cases: [{name: a, token: x, claims: {sub: a}}]}`,
+		`provider: [`,
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			if _, err := Parse([]byte(tc), "."); err == nil {
+				t.Fatalf("Parse(%s) -> expect exception", tc)
+			}
+		})
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	s, err := Parse([]byte(`
+provider:
+  attributeMapping:
+    google.subject: assertion.sub
+  attributeCondition: assertion.owner == 'octo-org' || assertion.owner == 'partner-org'
+cases:
+  - claims: {sub: a, owner: octo-org}
+  - claims: {sub: b, owner: evil-org}
+    expect:
+      accepted: false
+  - token: not-a-token
+    expect:
+      error: unsupported
+`), ".")
+
+	if err != nil {
+		t.Fatalf("Parse() = %s, expected no error", err)
+	}
+
+	report, err := s.Coverage()
+
+	if err != nil {
+		t.Fatalf("Coverage() = %s, expected no error", err)
+	}
+	if len(report.Branches) != 2 || report.Covered() != 1 || report.Errors != 1 {
+		t.Fatalf("Coverage() = %d/%d covered branches and %d errors, expected 1/2 and 1 error", report.Covered(), len(report.Branches), report.Errors)
+	}
+}
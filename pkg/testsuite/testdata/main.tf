@@ -0,0 +1,12 @@
+resource "google_iam_workload_identity_pool_provider" "github" {
+  workload_identity_pool_id          = "ci-pool"
+  workload_identity_pool_provider_id = "github"
+  attribute_mapping = {
+    "google.subject"       = "assertion.sub"
+    "attribute.repository" = "assertion.repository"
+  }
+  attribute_condition = "assertion.repository_owner == 'octo-org'"
+  oidc {
+    issuer_uri = "https://token.actions.githubusercontent.com"
+  }
+}
@@ -0,0 +1,255 @@
+// Package inspect decodes a token (JWT, JSON document or SAML response) and flags the claims
+// that will cause trouble with Workload Identity Federation.
+// It's apart from the token package so that decoding a token doesn't link in encoding/xml (eg. in wif-go.wasm).
+package inspect
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/attribute"
+	"github.com/loicsikidi/wif-go/pkg/token"
+)
+
+// Kinds of token recognized by Inspect.
+const (
+	KindJWT  = "jwt"
+	KindJSON = "json"
+	KindSAML = "saml"
+)
+
+// timeClaims are the JWT claims holding a NumericDate (ie. seconds since the epoch)
+var timeClaims = []string{"iat", "nbf", "exp", "auth_time"}
+
+// Time is a date carried by a token (eg. exp)
+type Time struct {
+	// Name of the claim (or of the SAML attribute, eg. NotOnOrAfter).
+	Name string `json:"name"`
+	// Time in UTC.
+	Time time.Time `json:"time"`
+}
+
+// Issue is a claim that will cause trouble with Workload Identity Federation
+type Issue struct {
+	// Path of the claim as written in an attribute mapping (eg. assertion.sub).
+	Path string `json:"path"`
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// Inspection is the decoded content of a token
+type Inspection struct {
+	// Kind of the token (jwt, json or saml).
+	Kind string `json:"kind"`
+	// Header of a JWT.
+	Header map[string]any `json:"header,omitempty"`
+	// Claims as exposed to an attribute mapping through 'assertion'.
+	Claims map[string]any `json:"claims"`
+	// Times are the dates carried by the token.
+	Times []*Time `json:"times,omitempty"`
+	// Issues are the claims that will cause trouble with Workload Identity Federation.
+	Issues []*Issue `json:"issues,omitempty"`
+}
+
+// Paths returns the claim paths as written in an attribute mapping (eg. assertion.sub)
+func (i *Inspection) Paths() []string {
+	return Paths(attribute.Assertion, i.Claims)
+}
+
+// Inspect decodes a JWT (without verifying its signature), a JSON document holding the claims
+// or a SAML response (either base64 encoded or raw XML)
+func Inspect(raw string) (*Inspection, error) {
+	raw = strings.TrimSpace(raw)
+
+	if strings.HasPrefix(raw, "<") {
+		return inspectSAML([]byte(raw))
+	}
+
+	if !strings.HasPrefix(raw, "{") && strings.Count(raw, ".") != 2 {
+		xmlDoc, err := base64.StdEncoding.DecodeString(raw)
+
+		if err != nil || !strings.HasPrefix(strings.TrimSpace(string(xmlDoc)), "<") {
+			return nil, fmt.Errorf("unsupported token format: expected a JWT, a JSON document or a SAML response")
+		}
+		return inspectSAML(xmlDoc)
+	}
+
+	payload, err := token.Payload(raw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	i := &Inspection{Kind: KindJSON}
+
+	if strings.Count(raw, ".") == 2 && !strings.HasPrefix(raw, "{") {
+		i.Kind = KindJWT
+		header, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.Split(raw, ".")[0], "="))
+
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JWT header: %w", err)
+		}
+		if err := json.Unmarshal(header, &i.Header); err != nil {
+			return nil, fmt.Errorf("the JWT header is not a valid JSON object: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(payload), &i.Claims); err != nil {
+		return nil, fmt.Errorf("the token claims must be a JSON object: %w", err)
+	}
+
+	for _, name := range timeClaims {
+		if seconds, ok := i.Claims[name].(float64); ok {
+			i.Times = append(i.Times, &Time{Name: name, Time: time.Unix(int64(seconds), 0).UTC()})
+		}
+	}
+
+	i.checkSubject("sub")
+	i.checkClaims(attribute.Assertion, i.Claims)
+	return i, nil
+}
+
+// checkSubject flags a subject claim that can't be mapped to google.subject as is
+func (i *Inspection) checkSubject(name string) {
+	path := Path(attribute.Assertion, name)
+	sub, ok := i.Claims[name]
+	value, isString := sub.(string)
+
+	switch {
+	case !ok || (isString && value == ""):
+		i.issue(path, "the claim is missing, google.subject must be mapped from another claim")
+	case !isString:
+		i.issue(path, "the claim is not a string, it must be converted (eg. string(%s)) to be mapped to google.subject", path)
+	case len(value) > compiler.MaximumSubjectLengthInBytes:
+		i.issue(path, "the value (%d bytes) exceeds the %d bytes limit of google.subject", len(value), compiler.MaximumSubjectLengthInBytes)
+	}
+}
+
+func (i *Inspection) issue(path, format string, a ...any) {
+	i.Issues = append(i.Issues, &Issue{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// checkClaims flags claim names requiring brackets and oversized values
+func (i *Inspection) checkClaims(parent string, claims map[string]any) {
+	names := make([]string, 0, len(claims))
+	for name := range claims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := Path(parent, name)
+
+		if !IsIdentifier(name) {
+			if strings.Contains(name, ".") {
+				i.issue(path, "the claim name contains a dot, %s.%s would read a nested claim", parent, name)
+			} else {
+				i.issue(path, "the claim name isn't a CEL identifier, it must be accessed with brackets")
+			}
+		}
+
+		switch value := claims[name].(type) {
+		case map[string]any:
+			i.checkClaims(path, value)
+		case string:
+			if len(value) > compiler.MaximumCustomAttributesLengthInBytes {
+				i.issue(path, "the value (%d bytes) exceeds the %d bytes limit of mapped attributes", len(value), compiler.MaximumCustomAttributesLengthInBytes)
+			}
+		case []any:
+			size := 0
+			for _, v := range value {
+				if s, ok := v.(string); ok {
+					size += len(s)
+				}
+			}
+			if size > compiler.MaximumCustomAttributesLengthInBytes {
+				i.issue(path, "the values (%d bytes) exceed the %d bytes limit of mapped attributes", size, compiler.MaximumCustomAttributesLengthInBytes)
+			}
+		}
+	}
+}
+
+type samlAttribute struct {
+	Name   string   `xml:"Name,attr"`
+	Values []string `xml:"AttributeValue"`
+}
+
+type samlAssertion struct {
+	IssueInstant string `xml:"IssueInstant,attr"`
+	Subject      struct {
+		NameID string `xml:"NameID"`
+	} `xml:"Subject"`
+	Conditions struct {
+		NotBefore    string `xml:"NotBefore,attr"`
+		NotOnOrAfter string `xml:"NotOnOrAfter,attr"`
+	} `xml:"Conditions"`
+	Attributes []samlAttribute `xml:"AttributeStatement>Attribute"`
+}
+
+type samlResponse struct {
+	XMLName   xml.Name
+	Assertion *samlAssertion `xml:"Assertion"`
+}
+
+// inspectSAML decodes a SAML 2.0 response.
+// Claims follow the SAML attribute mapping of Workload Identity Federation,
+// ie. assertion.subject and assertion.attributes['name'] (a list of strings).
+func inspectSAML(doc []byte) (*Inspection, error) {
+	resp := &samlResponse{}
+
+	if err := xml.Unmarshal(doc, resp); err != nil {
+		return nil, fmt.Errorf("error decoding SAML response: %w", err)
+	}
+
+	assertion := resp.Assertion
+	if resp.XMLName.Local == "Assertion" {
+		// the document is the assertion itself
+		assertion = &samlAssertion{}
+		if err := xml.Unmarshal(doc, assertion); err != nil {
+			return nil, fmt.Errorf("error decoding SAML assertion: %w", err)
+		}
+	}
+	if assertion == nil {
+		return nil, fmt.Errorf("the SAML response doesn't hold an assertion (encrypted assertions aren't supported)")
+	}
+
+	attributes := map[string]any{}
+	for _, a := range assertion.Attributes {
+		values := []any{}
+		if v, ok := attributes[a.Name].([]any); ok {
+			values = v
+		}
+		for _, v := range a.Values {
+			values = append(values, strings.TrimSpace(v))
+		}
+		attributes[a.Name] = values
+	}
+
+	i := &Inspection{
+		Kind: KindSAML,
+		Claims: map[string]any{
+			"subject":    strings.TrimSpace(assertion.Subject.NameID),
+			"attributes": attributes,
+		},
+	}
+
+	for _, t := range []struct{ name, value string }{
+		{"IssueInstant", assertion.IssueInstant},
+		{"NotBefore", assertion.Conditions.NotBefore},
+		{"NotOnOrAfter", assertion.Conditions.NotOnOrAfter},
+	} {
+		if parsed, err := time.Parse(time.RFC3339, t.value); err == nil {
+			i.Times = append(i.Times, &Time{Name: t.name, Time: parsed.UTC()})
+		}
+	}
+
+	i.checkSubject("subject")
+	i.checkClaims(attribute.Assertion, i.Claims)
+	return i, nil
+}
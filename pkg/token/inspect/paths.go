@@ -0,0 +1,49 @@
+package inspect
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// reservedWords can't be used as CEL identifiers
+//
+// (See more at https://github.com/google/cel-spec/blob/master/doc/langdef.md#syntax)
+var reservedWords = map[string]bool{
+	"true": true, "false": true, "null": true, "in": true, "as": true, "break": true, "const": true,
+	"continue": true, "else": true, "for": true, "function": true, "if": true, "import": true, "let": true,
+	"loop": true, "package": true, "namespace": true, "return": true, "var": true, "void": true, "while": true,
+}
+
+// IsIdentifier tells whether a claim name can be accessed with a dot (eg. assertion.sub)
+// or requires a bracket (eg. assertion['https://example.com/groups'])
+func IsIdentifier(name string) bool {
+	return identifierRegexp.MatchString(name) && !reservedWords[name]
+}
+
+// Path returns the CEL expression accessing a claim of a parent expression
+func Path(parent, name string) string {
+	if IsIdentifier(name) {
+		return fmt.Sprintf("%s.%s", parent, name)
+	}
+	return fmt.Sprintf("%s['%s']", parent, strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), `'`, `\'`))
+}
+
+// Paths returns the paths of every claim (nested ones included) as written in a CEL expression,
+// sorted alphabetically (eg. assertion.sub, assertion['https://example.com/groups'])
+func Paths(root string, claims map[string]any) []string {
+	var paths []string
+	for name, value := range claims {
+		path := Path(root, name)
+		paths = append(paths, path)
+
+		if nested, ok := value.(map[string]any); ok {
+			paths = append(paths, Paths(path, nested)...)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
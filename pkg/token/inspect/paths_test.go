@@ -0,0 +1,30 @@
+package inspect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPaths(t *testing.T) {
+	claims := map[string]any{
+		"sub":                        "1234567890",
+		"in":                         true,
+		"https://example.com/groups": []any{"admins"},
+		"it's":                       "quoted",
+		"address":                    map[string]any{"country": "FR", "postal.code": "75001"},
+	}
+
+	expected := []string{
+		"assertion.address",
+		"assertion.address.country",
+		"assertion.address['postal.code']",
+		"assertion.sub",
+		"assertion['https://example.com/groups']",
+		"assertion['in']",
+		`assertion['it\'s']`,
+	}
+
+	if got := Paths("assertion", claims); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Paths() = %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,206 @@
+// Package jwks verifies JWT signatures against a JSON Web Key Set.
+// It's apart from the token package so that decoding a token doesn't link in the crypto packages (eg. in wif-go.wasm).
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	// Link in the hash functions of the algorithms
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// ErrInvalidSignature means that none of the keys of the JWKS verifies the JWT signature.
+var ErrInvalidSignature = errors.New("the JWT signature can't be verified by the JWKS")
+
+// algorithms are the JWT signing algorithms accepted by Google Cloud Platform, by name (cf. RFC 7518)
+var algorithms = map[string]struct {
+	kty  string
+	hash crypto.Hash
+	pss  bool
+}{
+	"RS256": {kty: "RSA", hash: crypto.SHA256},
+	"RS384": {kty: "RSA", hash: crypto.SHA384},
+	"RS512": {kty: "RSA", hash: crypto.SHA512},
+	"PS256": {kty: "RSA", hash: crypto.SHA256, pss: true},
+	"PS384": {kty: "RSA", hash: crypto.SHA384, pss: true},
+	"PS512": {kty: "RSA", hash: crypto.SHA512, pss: true},
+	"ES256": {kty: "EC", hash: crypto.SHA256},
+	"ES384": {kty: "EC", hash: crypto.SHA384},
+	"ES512": {kty: "EC", hash: crypto.SHA512},
+}
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+// decodeSegment decodes a base64url JWT segment or JWK parameter (padding is tolerated)
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+}
+
+// jwk is a public JSON Web Key (cf. RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey decodes the public key of a JWK
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	integer := func(v string) (*big.Int, error) {
+		b, err := decodeSegment(v)
+
+		if err != nil || len(b) == 0 {
+			return nil, fmt.Errorf("invalid key %q: malformed parameter", k.Kid)
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := integer(k.N)
+
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := integer(k.E)
+
+		if err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid key %q: malformed exponent", k.Kid)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("invalid key %q: unsupported curve %q", k.Kid, k.Crv)
+		}
+
+		x, err := integer(k.X)
+
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := integer(k.Y)
+
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("invalid key %q: unsupported key type %q", k.Kid, k.Kty)
+	}
+}
+
+// verify checks a signature with the public key of a JWK
+func verify(key crypto.PublicKey, alg string, signed, signature []byte) bool {
+	a := algorithms[alg]
+	h := a.hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if a.pss {
+			return rsa.VerifyPSS(k, a.hash, digest, signature, nil) == nil
+		}
+		return rsa.VerifyPKCS1v15(k, a.hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	default:
+		return false
+	}
+}
+
+// Verify checks the signature of a JWT against a JSON Web Key Set (eg. the JWKS of an OIDC provider).
+//
+// Keys are selected by the 'kid' and 'alg' header parameters of the JWT, the claims (eg. 'exp') aren't checked.
+func Verify(raw string, jwks string) error {
+	segments := strings.Split(strings.TrimSpace(raw), ".")
+
+	if len(segments) != 3 {
+		return fmt.Errorf("only a signed JWT can be verified")
+	}
+
+	rawHeader, err := decodeSegment(segments[0])
+
+	if err != nil {
+		return fmt.Errorf("error decoding JWT header: %w", err)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return fmt.Errorf("error unmarshaling JWT header: %w", err)
+	}
+
+	a, ok := algorithms[header.Alg]
+	if !ok {
+		return fmt.Errorf("unsupported JWT algorithm %q", header.Alg)
+	}
+
+	signature, err := decodeSegment(segments[2])
+
+	if err != nil {
+		return fmt.Errorf("error decoding JWT signature: %w", err)
+	}
+
+	var set struct {
+		Keys []*jwk `json:"keys"`
+	}
+	if err := json.Unmarshal([]byte(jwks), &set); err != nil {
+		return fmt.Errorf("error unmarshaling JWKS: %w", err)
+	}
+
+	for i, k := range set.Keys {
+		if k == nil {
+			return fmt.Errorf("invalid JWKS: key %d is null", i)
+		}
+	}
+
+	signed := []byte(segments[0] + "." + segments[1])
+	for _, k := range set.Keys {
+		if k.Kty != a.kty || header.Kid != "" && k.Kid != header.Kid || k.Alg != "" && k.Alg != header.Alg {
+			continue
+		}
+
+		key, err := k.publicKey()
+
+		if err != nil {
+			return err
+		}
+
+		if verify(key, header.Alg, signed, signature) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
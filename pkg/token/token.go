@@ -0,0 +1,53 @@
+package token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedToken means that a token is neither a JWT nor a JSON document.
+var ErrUnsupportedToken = errors.New("unsupported token format: expected a JWT or a JSON document")
+
+// isJSON tells whether a document is valid JSON.
+//
+// json.Unmarshal is used rather than json.Valid since the decoder is linked in anyway (cf. cmd/wasm/size_test.go).
+func isJSON(doc []byte) bool {
+	var raw json.RawMessage
+	return json.Unmarshal(doc, &raw) == nil
+}
+
+// Payload returns the JSON claims carried by a token, which is used as compiler.Input.Payload.
+//
+// The token is either a JWT (only the payload is decoded, the signature isn't verified)
+// or a JSON document holding the claims.
+func Payload(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+
+	if strings.HasPrefix(raw, "{") {
+		if !isJSON([]byte(raw)) {
+			return "", fmt.Errorf("the token is not a valid JSON document")
+		}
+		return raw, nil
+	}
+
+	segments := strings.Split(raw, ".")
+
+	if len(segments) != 3 {
+		return "", ErrUnsupportedToken
+	}
+
+	// padding is tolerated
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segments[1], "="))
+
+	if err != nil {
+		return "", fmt.Errorf("error decoding JWT payload: %w", err)
+	}
+
+	if !isJSON(payload) {
+		return "", fmt.Errorf("the JWT payload is not a valid JSON document")
+	}
+	return string(payload), nil
+}
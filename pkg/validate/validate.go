@@ -0,0 +1,328 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common"
+	"github.com/loicsikidi/wif-go/pkg/compiler"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider"
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/attribute"
+	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+)
+
+// Severities of a diagnostic.
+const (
+	// SeverityError means that the configuration is rejected by Google Cloud Platform.
+	SeverityError = "error"
+	// SeverityWarning means that the configuration is accepted but is likely wrong.
+	SeverityWarning = "warning"
+)
+
+var customAttributeName = regexp.MustCompile("^[a-z0-9_]{1,100}$")
+
+// Diagnostic is an issue found in an attribute mapping or an attribute condition
+type Diagnostic struct {
+	// Attribute is the key of the attribute mapping (eg. google.subject),
+	// it's empty when the issue is in the attribute condition.
+	Attribute string
+	// Severity of the issue (eg. SeverityError).
+	Severity string
+	// Message describes the issue.
+	Message string
+	// Line of the issue in the expression, starting at 1 (0 when the issue concerns the whole expression).
+	Line int
+	// Column of the issue in the expression, starting at 1 (0 when the issue concerns the whole expression).
+	Column int
+	// EndLine and EndColumn are the end of the issue, EndColumn being the column after its last character
+	// (0 when the issue concerns the whole expression).
+	EndLine   int
+	EndColumn int
+}
+
+// span is the range of an issue in an expression
+type span struct {
+	line, column, endLine, endColumn int
+}
+
+// whole is the span of an issue concerning the whole expression
+var whole = span{}
+
+// spanAt returns the span of the word (eg. an identifier) at a location of an expression, or ending there
+// (eg. a function name when the location is its opening parenthesis), or of a single character when there is no word
+func spanAt(source common.Source, line, column int) span {
+	s := span{line: line, column: column + 1, endLine: line, endColumn: column + 2}
+
+	snippet, ok := source.Snippet(line)
+	if !ok {
+		return s
+	}
+
+	runes := []rune(snippet)
+	isWord := func(i int) bool {
+		return i >= 0 && i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_')
+	}
+
+	start, end := column, column
+	if !isWord(column) {
+		end = column - 1
+		if !isWord(end) {
+			return s
+		}
+	}
+
+	for isWord(start - 1) {
+		start--
+	}
+	for isWord(end + 1) {
+		end++
+	}
+	return span{line: line, column: start + 1, endLine: line, endColumn: end + 2}
+}
+
+// Valid tells whether diagnostics hold no error
+func Valid(diagnostics []*Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// Options of a validation
+type Options struct {
+	// Workforce validates a Workforce Identity Federation configuration (cf. compiler.Compiler.Workforce).
+	Workforce bool
+}
+
+// validator collects the diagnostics of a configuration
+type validator struct {
+	Options
+	diagnostics []*Diagnostic
+}
+
+func (v *validator) add(attr, severity string, at span, format string, args ...any) {
+	v.diagnostics = append(v.diagnostics, &Diagnostic{
+		Attribute: attr,
+		Severity:  severity,
+		Message:   fmt.Sprintf(format, args...),
+		Line:      at.line,
+		Column:    at.column,
+		EndLine:   at.endLine,
+		EndColumn: at.endColumn,
+	})
+}
+
+// Validate checks an attribute mapping and an attribute condition without a token,
+// the way Google Cloud Platform does when a provider is created.
+// Diagnostics of the mapping come first (google.subject, google.groups then custom attributes), followed by the ones of the condition.
+func Validate(p provider.Provider, mapping map[string]string, condition string) []*Diagnostic {
+	return Options{}.Validate(p, mapping, condition)
+}
+
+// Validate checks an attribute mapping and an attribute condition with options (cf. Validate)
+func (o Options) Validate(p provider.Provider, mapping map[string]string, condition string) []*Diagnostic {
+	v := &validator{Options: o}
+
+	env, err := compiler.NewEnv(p)
+
+	if err != nil {
+		v.add("", SeverityError, whole, "error creating CEL environment: %s", err)
+		return v.diagnostics
+	}
+
+	if _, ok := mapping[compiler.GoogleSubject]; !ok {
+		v.add(compiler.GoogleSubject, SeverityError, whole, "missing '%s' attribute", compiler.GoogleSubject)
+	}
+
+	custom := 0
+	for _, k := range Keys(mapping) {
+		if strings.HasPrefix(k, attribute.Attribute+".") {
+			custom++
+		}
+		v.mapping(env, k, mapping[k])
+	}
+
+	if custom > compiler.MaximumCustomAttributes {
+		v.add("", SeverityError, whole, "custom attributes are limited to %d", compiler.MaximumCustomAttributes)
+	}
+
+	if condition != "" {
+		v.condition(mapping, condition)
+	}
+	return v.diagnostics
+}
+
+// Keys returns the keys of an attribute mapping: google.subject, google.groups then the custom attributes in alphabetical order
+func Keys(mapping map[string]string) []string {
+	keys := make([]string, 0, len(mapping))
+	for k := range mapping {
+		keys = append(keys, k)
+	}
+
+	rank := func(k string) int {
+		switch k {
+		case compiler.GoogleSubject:
+			return 0
+		case compiler.GoogleGroups:
+			return 1
+		default:
+			return 2
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := rank(keys[i]), rank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// mapping checks an entry of the attribute mapping
+func (v *validator) mapping(env *cel.Env, key, expr string) {
+	if err := compiler.InvalidMappingKey(key, v.Workforce); err != nil {
+		v.add(key, SeverityError, whole, "%s", strings.ReplaceAll(err.Error(), "\n", " "))
+		return
+	}
+
+	if name := strings.TrimPrefix(key, attribute.Attribute+"."); name != key && !customAttributeName.MatchString(name) {
+		v.add(key, SeverityError, whole, "invalid mapped attribute key: %s. The maximum length of a mapped attribute key is 100 characters and may only contain the characters [a-z0-9_]", name)
+	}
+
+	if len(expr) > compiler.MaximumAttributeExpressionLengthInBytes {
+		v.add(key, SeverityError, whole, "the maximum length of an attribute mapping expression is %d characters", compiler.MaximumAttributeExpressionLengthInBytes)
+		return
+	}
+
+	ast := v.compile(env, key, expr)
+	if ast == nil {
+		return
+	}
+
+	t := ast.ResultType()
+	if key == compiler.GoogleGroups {
+		if !isDyn(t) && (t.GetListType() == nil || !isDyn(t.GetListType().GetElemType()) && !isPrimitive(t.GetListType().GetElemType(), exprpb.Type_STRING)) {
+			v.add(key, SeverityError, whole, "the mapped attribute '%s' must be of type LIST<STRING>", key)
+		}
+	} else if !isDyn(t) && !isPrimitive(t, exprpb.Type_STRING) {
+		v.add(key, SeverityError, whole, "the mapped attribute '%s' must be of type STRING", key)
+	}
+}
+
+// condition checks the attribute condition
+func (v *validator) condition(mapping map[string]string, condition string) {
+	if len(condition) > compiler.MaximumAttributeConditionLengthInBytes {
+		v.add("", SeverityError, whole, "the maximum length of an attribute condition expression is %d characters", compiler.MaximumAttributeConditionLengthInBytes)
+		return
+	}
+
+	env, err := compiler.NewEnv(&attribute.Provider{})
+
+	if err != nil {
+		v.add("", SeverityError, whole, "error creating attribute condition CEL environment: %s", err)
+		return
+	}
+
+	ast := v.compile(env, "", condition)
+	if ast == nil {
+		return
+	}
+
+	if t := ast.ResultType(); !isDyn(t) && !isPrimitive(t, exprpb.Type_BOOL) {
+		v.add("", SeverityError, whole, "the attribute condition must be of type BOOL")
+	}
+
+	// attributes which aren't mapped are always absent
+	source := common.NewTextSource(condition)
+	info := ast.SourceInfo()
+	walk(ast.Expr(), func(e *exprpb.Expr) {
+		sel := e.GetSelectExpr()
+		if sel == nil || sel.GetTestOnly() {
+			return
+		}
+
+		var key string
+		switch name := sel.GetOperand().GetIdentExpr().GetName(); name {
+		case attribute.Attribute, attribute.Google:
+			key = name + "." + sel.GetField()
+		default:
+			return
+		}
+
+		if _, ok := mapping[key]; ok {
+			return
+		}
+
+		at := whole
+		if loc, ok := source.OffsetLocation(info.GetPositions()[sel.GetOperand().GetId()]); ok {
+			at = span{line: loc.Line(), column: loc.Column() + 1, endLine: loc.Line(), endColumn: loc.Column() + 1 + len([]rune(key))}
+		}
+		v.add("", SeverityWarning, at, "'%s' is not mapped by the attribute mapping", key)
+	})
+}
+
+// compile compiles an expression, issues are added as diagnostics
+func (v *validator) compile(env *cel.Env, key, expr string) *cel.Ast {
+	if strings.Contains(expr, "timestamp(int(") {
+		v.add(key, SeverityError, whole, "create a timestamp using unix timestamp is not currently supported by the Workload Identity Federation CEL implementation")
+		return nil
+	}
+
+	ast, issues := env.Compile(expr)
+
+	if issues.Err() != nil {
+		source := common.NewTextSource(expr)
+		for _, e := range issues.Errors() {
+			v.add(key, SeverityError, spanAt(source, e.Location.Line(), e.Location.Column()), "%s", e.Message)
+		}
+		return nil
+	}
+	return ast
+}
+
+func isDyn(t *exprpb.Type) bool {
+	return t.GetDyn() != nil || t.GetWellKnown() == exprpb.Type_ANY
+}
+
+func isPrimitive(t *exprpb.Type, p exprpb.Type_PrimitiveType) bool {
+	return t.GetPrimitive() == p
+}
+
+// walk calls fn on every node of an expression
+func walk(e *exprpb.Expr, fn func(*exprpb.Expr)) {
+	if e == nil {
+		return
+	}
+
+	fn(e)
+	switch k := e.GetExprKind().(type) {
+	case *exprpb.Expr_SelectExpr:
+		walk(k.SelectExpr.GetOperand(), fn)
+	case *exprpb.Expr_CallExpr:
+		walk(k.CallExpr.GetTarget(), fn)
+		for _, arg := range k.CallExpr.GetArgs() {
+			walk(arg, fn)
+		}
+	case *exprpb.Expr_ListExpr:
+		for _, elem := range k.ListExpr.GetElements() {
+			walk(elem, fn)
+		}
+	case *exprpb.Expr_StructExpr:
+		for _, entry := range k.StructExpr.GetEntries() {
+			walk(entry.GetMapKey(), fn)
+			walk(entry.GetValue(), fn)
+		}
+	case *exprpb.Expr_ComprehensionExpr:
+		walk(k.ComprehensionExpr.GetIterRange(), fn)
+		walk(k.ComprehensionExpr.GetAccuInit(), fn)
+		walk(k.ComprehensionExpr.GetLoopCondition(), fn)
+		walk(k.ComprehensionExpr.GetLoopStep(), fn)
+		walk(k.ComprehensionExpr.GetResult(), fn)
+	}
+}
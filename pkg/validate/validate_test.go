@@ -0,0 +1,117 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/loicsikidi/wif-go/pkg/compiler/provider/oidc"
+)
+
+func TestValidate(t *testing.T) {
+	type Expected struct {
+		attribute string
+		severity  string
+		line      int
+		column    int
+		endColumn int
+		message   string
+	}
+	tests := []struct {
+		mapping   map[string]string
+		condition string
+		workforce bool
+		expected  []*Expected
+	}{
+		{
+			mapping:   map[string]string{"google.subject": "assertion.sub", "attribute.org": "assertion.org"},
+			condition: "attribute.org == 'octo-org'",
+		},
+		{
+			mapping: map[string]string{"google.groups": "assertion.groups"},
+			expected: []*Expected{
+				{attribute: "google.subject", severity: SeverityError, message: "missing 'google.subject' attribute"},
+			},
+		},
+		{
+			mapping: map[string]string{"google.subject": "assertion.sub", "attribute.Org": "assertion.org", "google.email": "assertion.email"},
+			expected: []*Expected{
+				{attribute: "attribute.Org", severity: SeverityError, message: "invalid mapped attribute key: Org"},
+				{attribute: "google.email", severity: SeverityError, message: "invalid attribute mapping key: google.email"},
+			},
+		},
+		{
+			mapping: map[string]string{"google.subject": "assertion.sub +\n  ", "google.groups": "'admins'", "attribute.id": "1"},
+			expected: []*Expected{
+				{attribute: "google.subject", severity: SeverityError, line: 2, column: 3, endColumn: 4, message: "Syntax error"},
+				{attribute: "google.groups", severity: SeverityError, message: "must be of type LIST<STRING>"},
+				{attribute: "attribute.id", severity: SeverityError, message: "must be of type STRING"},
+			},
+		},
+		{
+			mapping:   map[string]string{"google.subject": strings.Repeat("a", 2049)},
+			condition: "timestamp(int(assertion.iat)) > now",
+			expected: []*Expected{
+				{attribute: "google.subject", severity: SeverityError, message: "the maximum length of an attribute mapping expression is 2048 characters"},
+				{severity: SeverityError, message: "unix timestamp is not currently supported"},
+			},
+		},
+		{
+			mapping:   map[string]string{"google.subject": "assertion.sub"},
+			condition: "assertion.org == 'octo-org' &&\n  attribute.role == 'admin' && 'admins' in google.groups",
+			expected: []*Expected{
+				{severity: SeverityWarning, line: 2, column: 3, endColumn: 17, message: "'attribute.role' is not mapped"},
+				{severity: SeverityWarning, line: 2, column: 44, endColumn: 57, message: "'google.groups' is not mapped"},
+			},
+		},
+		{
+			mapping:   map[string]string{"google.subject": "assertion.sub"},
+			condition: "assertion.org",
+		},
+		{
+			mapping: map[string]string{"google.subject": "assertion.sub", "google.display_name": "upper(assertion.name)"},
+			expected: []*Expected{
+				{attribute: "google.display_name", severity: SeverityError, message: "invalid attribute mapping key: google.display_name"},
+			},
+		},
+		{
+			mapping:   map[string]string{"google.subject": "assertion.sub", "google.display_name": "upper(assertion.name)"},
+			workforce: true,
+			expected: []*Expected{
+				{attribute: "google.display_name", severity: SeverityError, line: 1, column: 1, endColumn: 6, message: "undeclared reference to 'upper'"},
+			},
+		},
+		{
+			mapping:   map[string]string{"google.subject": "assertion.sub"},
+			condition: "size(assertion.org)",
+			expected: []*Expected{
+				{severity: SeverityError, message: "the attribute condition must be of type BOOL"},
+			},
+		},
+	}
+
+	for i, tst := range tests {
+		tc := tst
+		t.Run(fmt.Sprintf("[%d]", i), func(t *testing.T) {
+			diagnostics := Options{Workforce: tc.workforce}.Validate(&oidc.Provider{}, tc.mapping, tc.condition)
+
+			if len(diagnostics) != len(tc.expected) {
+				for _, d := range diagnostics {
+					t.Logf("%+v", d)
+				}
+				t.Fatalf("Validate() = %d diagnostics, expected %d", len(diagnostics), len(tc.expected))
+			}
+
+			for j, d := range diagnostics {
+				e := tc.expected[j]
+				if d.Attribute != e.attribute || d.Severity != e.severity || d.Line != e.line || d.Column != e.column || d.EndLine != e.line || d.EndColumn != e.endColumn || !strings.Contains(d.Message, e.message) {
+					t.Fatalf("diagnostic [%d] = %+v, expected %+v", j, d, e)
+				}
+			}
+
+			if valid := Valid(diagnostics); valid != (len(tc.expected) == 0 || tc.expected[0].severity == SeverityWarning) {
+				t.Fatalf("Valid() = %t", valid)
+			}
+		})
+	}
+}
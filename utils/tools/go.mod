@@ -2,6 +2,9 @@ module github.com/loicsikidi/wif-go/utils/tools
 
 go 1.20
 
-require google.golang.org/protobuf v1.30.0
+require (
+	google.golang.org/grpc/cmd/protoc-gen-go-grpc v1.3.0
+	google.golang.org/protobuf v1.30.0
+)
 
 require github.com/google/go-cmp v0.5.9 // indirect